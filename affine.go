@@ -0,0 +1,110 @@
+package levenshtein
+
+import "math"
+
+// gapInfinity represents an impossible state in the gap layers of an affine
+// gap matrix. It is small enough that adding costs to it cannot overflow.
+const gapInfinity = math.MaxInt / 4
+
+// gapLayer identifies one of the layers of an affine gap matrix.
+type gapLayer int8
+
+const (
+	bestLayer   gapLayer = iota // Best cost, ending in any operation
+	insertLayer                 // Best cost, ending in an insertion
+	removeLayer                 // Best cost, ending in a removal
+)
+
+// SetAffineGap is an option which applies affine gap costs, as used in
+// sequence alignment: the first insertion or removal in a consecutive run
+// costs open, and each subsequent one costs extend, so a run of n
+// insertions costs open + (n-1)*extend rather than n times the insertion
+// cost. This favours a few long gaps over many short ones. The matrix is
+// filled using Gotoh's algorithm, which requires two extra layers to track
+// whether each cell ends in a run of insertions or removals, tripling the
+// memory required. If this option is provided, any insertion and removal
+// costs set by other options are ignored, though swap costs still apply.
+func SetAffineGap(open, extend int) Option {
+	return func(m *Matrix) {
+		m.affine = true
+		m.gapOpen = open
+		m.gapExtend = extend
+	}
+}
+
+// fillAffine fills the matrix using Gotoh's algorithm. The insertion and
+// removal layers hold the best cost of reaching each cell via an operation of
+// that type, while the matrix itself holds the best cost overall.
+func (m *Matrix) fillAffine() {
+	m.gapInsert = newMatrix(m.source, m.target)
+	m.gapRemove = newMatrix(m.source, m.target)
+	best, ins, rem := m.matrix, m.gapInsert, m.gapRemove
+
+	ins[0][0], rem[0][0] = gapInfinity, gapInfinity
+	for j := 1; j <= len(m.target); j++ {
+		ins[0][j] = m.gapOpen + (j-1)*m.gapExtend
+		rem[0][j] = gapInfinity
+		best[0][j] = ins[0][j]
+	}
+	for i := 1; i <= len(m.source); i++ {
+		rem[i][0] = m.gapOpen + (i-1)*m.gapExtend
+		ins[i][0] = gapInfinity
+		best[i][0] = rem[i][0]
+
+		for j := 1; j <= len(m.target); j++ {
+			ins[i][j] = min(best[i][j-1]+m.gapOpen, ins[i][j-1]+m.gapExtend)
+			rem[i][j] = min(best[i-1][j]+m.gapOpen, rem[i-1][j]+m.gapExtend)
+			best[i][j] = min(ins[i][j], rem[i][j], best[i-1][j-1]+m.swap(i, j))
+		}
+	}
+}
+
+// walkAffine is the equivalent of walk for matrices filled using Gotoh's
+// algorithm, starting from cell (i, j) in the given layer.
+func (m *Matrix) walkAffine(i, j int, layer gapLayer, visit func(op Operation) bool) bool {
+	best, ins, rem := m.matrix, m.gapInsert, m.gapRemove
+	switch {
+	case layer == insertLayer:
+		next := bestLayer
+		if j > 1 && ins[i][j] == ins[i][j-1]+m.gapExtend {
+			next = insertLayer
+		}
+		return m.walkAffine(i, j-1, next, visit) && visit(Operation{
+			Type:           Insert,
+			Char:           m.target[j-1],
+			Index:          j - 1,
+			CumulativeCost: ins[i][j],
+		})
+	case layer == removeLayer:
+		next := bestLayer
+		if i > 1 && rem[i][j] == rem[i-1][j]+m.gapExtend {
+			next = removeLayer
+		}
+		return m.walkAffine(i-1, j, next, visit) && visit(Operation{
+			Type:           Remove,
+			Char:           m.source[i-1],
+			Index:          j,
+			CumulativeCost: rem[i][j],
+		})
+	case i == 0 && j == 0:
+		// Base case: we have reached the start of both strings
+		return true
+	case j > 0 && best[i][j] == ins[i][j]:
+		return m.walkAffine(i, j, insertLayer, visit)
+	case i > 0 && best[i][j] == rem[i][j]:
+		return m.walkAffine(i, j, removeLayer, visit)
+	default:
+		op := Operation{
+			Type:           Keep,
+			Char:           m.target[j-1],
+			Index:          j - 1,
+			CumulativeCost: best[i][j],
+		}
+		if m.source[i-1] != m.target[j-1] {
+			op.Type = Swap
+			op.FromChar = m.source[i-1]
+			op.IsCaseOnly = caseOnly(op.FromChar, op.Char)
+		}
+		return m.walkAffine(i-1, j-1, bestLayer, visit) && visit(op)
+	}
+}
@@ -0,0 +1,55 @@
+package levenshtein_test
+
+import (
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func TestSetAffineGap(t *testing.T) {
+	for _, test := range []struct {
+		source, target string
+		open, extend   int
+		dist           int
+		ops            string
+	}{
+		{"", "", 3, 1, 0, ""},
+		{"ab", "ab", 3, 1, 0, "KK"},
+		{"ab", "axyzb", 3, 1, 3 + 2*1, "KIIIK"},
+		{"axyzb", "ab", 3, 1, 3 + 2*1, "KRRRK"},
+		{"", "abc", 4, 2, 4 + 2*2, "III"},
+		{"abc", "", 4, 2, 4 + 2*2, "RRR"},
+		{"abcd", "xbcy", 5, 1, 2, "SKKS"},
+		// One long gap is preferred to two short ones
+		{"aaXbb", "aabb", 2, 1, 2, "KKRKK"},
+		{"abcdef", "abef", 3, 1, 4, "KKRRKK"},
+	} {
+		matrix := levenshtein.Build(test.source, test.target, levenshtein.SetAffineGap(test.open, test.extend))
+		if dist := matrix.Distance(); dist != test.dist {
+			t.Errorf("%q, %q: Distance() = %d, want %d", test.source, test.target, dist, test.dist)
+		}
+
+		ops := matrix.Operations()
+		var types []byte
+		for _, op := range ops {
+			types = append(types, "IRKS"[op.Type])
+		}
+		if string(types) != test.ops {
+			t.Errorf("%q, %q: Operations() types = %q, want %q", test.source, test.target, types, test.ops)
+		}
+		if result, err := levenshtein.Apply(test.source, ops); err != nil || result != test.target {
+			t.Errorf("%q, %q: Apply(Operations()) = %q, %v", test.source, test.target, result, err)
+		}
+		if err := levenshtein.VerifyResults(ops); err != nil {
+			t.Errorf("%q, %q: VerifyResults(Operations()): %v", test.source, test.target, err)
+		}
+		if len(ops) > 0 && ops[len(ops)-1].CumulativeCost != test.dist {
+			t.Errorf("%q, %q: final CumulativeCost = %d, want %d", test.source, test.target, ops[len(ops)-1].CumulativeCost, test.dist)
+		}
+
+		p := levenshtein.Compile(test.source, levenshtein.SetAffineGap(test.open, test.extend))
+		if dist := p.Distance(test.target); dist != test.dist {
+			t.Errorf("%q, %q: Compile().Distance() = %d, want %d", test.source, test.target, dist, test.dist)
+		}
+	}
+}
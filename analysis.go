@@ -0,0 +1,385 @@
+package levenshtein
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangedRunes returns the distinct characters which were inserted and
+// removed by the minimal list of edit operations, in the order in which they
+// were first seen. A swap counts as both the removal of the character it
+// replaced and the insertion of the character it swapped in. If the target
+// string cannot be reached within the limits on the number of operations (see
+// MaxInserts), there are no operations, and both lists are empty.
+func (m *Matrix) ChangedRunes() (inserted, removed []rune) {
+	if m.infeasible() {
+		return nil, nil
+	}
+	seenInserted, seenRemoved := map[rune]bool{}, map[rune]bool{}
+	insert := func(r rune) {
+		if !seenInserted[r] {
+			seenInserted[r] = true
+			inserted = append(inserted, r)
+		}
+	}
+	remove := func(r rune) {
+		if !seenRemoved[r] {
+			seenRemoved[r] = true
+			removed = append(removed, r)
+		}
+	}
+
+	m.walk(len(m.source), len(m.target), func(op Operation) bool {
+		switch op.Type {
+		case Insert:
+			insert(op.Char)
+		case Remove:
+			remove(op.Char)
+		case Swap:
+			remove(op.FromChar)
+			insert(op.Char)
+		}
+		return true
+	})
+	return inserted, removed
+}
+
+// UniquePath reports whether there is only one minimal list of edit
+// operations - i.e. whether no cell on any optimal path through the matrix can
+// be reached from more than one predecessor at the minimum cost. If it returns
+// false, the alignment returned by Operations is one of several equally good
+// alternatives, so the diff may be ambiguous. For matrices built with
+// SetAffineGap, only the final operation of each cell is considered. If the
+// target string cannot be reached within the limits on the number of
+// operations (see MaxInserts), there is no list of operations, so it returns
+// false.
+func (m *Matrix) UniquePath() bool {
+	if m.infeasible() {
+		return false
+	}
+
+	// Since the path is abandoned as soon as it branches, there is only ever
+	// a single path to follow back from the last cell. Once it reaches the
+	// first row or column, the rest of the path can only be insertions or
+	// removals, so it is unique.
+	i, j := len(m.source), len(m.target)
+	for i > 0 && j > 0 {
+		origin := m.origin(i, j)
+		if origin&(origin-1) != 0 {
+			return false // More than one predecessor
+		}
+		if c, ok := m.confusionStep(i, j); ok {
+			if origin != 0 || m.confusionSteps(i, j) > 1 {
+				return false
+			}
+			i, j = i-len(c.from), j-len(c.to)
+			continue
+		}
+		switch {
+		case origin&(1<<Insert) != 0:
+			j--
+		case origin&(1<<Remove) != 0:
+			i--
+		default:
+			i, j = i-1, j-1
+		}
+	}
+	return true
+}
+
+// AllOperationsLimited returns up to maxPaths of the minimal lists of edit
+// operations, starting with the one returned by Operations, along with whether
+// there were more than maxPaths of them (in which case the rest were not
+// enumerated). Ambiguous matrices can have enormous numbers of minimal lists,
+// so enumeration stops as soon as it finds one more than the limit. If
+// maxPaths is zero or negative, no lists are returned, and the result is
+// reported as truncated, since there is always at least one.
+//
+// No separate limit on depth or time is needed. The paths are traced
+// iteratively, so long strings do not cause deep recursion, and the depth of
+// the trace is at most the total length of the strings. Every branch explored
+// leads to a minimal list, since each step back is taken only if it is part
+// of one, so no time is spent on dead ends, and the time taken is
+// proportional to maxPaths times the length of the lists.
+//
+// Matrices built with SetAffineGap, ConfusionPairs, or limits on the number of
+// operations (see MaxInserts) are not enumerated: the list returned by
+// Operations is the only one returned, and it is reported as truncated. If the
+// target string cannot be reached within the limits, there are no lists, and
+// nothing is truncated.
+func (m *Matrix) AllOperationsLimited(maxPaths int) (paths [][]Operation, truncated bool) {
+	paths = [][]Operation{}
+	if m.infeasible() {
+		return paths, false
+	}
+	if maxPaths <= 0 {
+		return paths, true
+	}
+	if m.affine || m.confusions != nil || m.limited() {
+		return append(paths, m.Operations()), true
+	}
+
+	// Each frame is a cell on the current path, along with the index of the
+	// next type of operation to try in order to step back from it. There is
+	// one fewer operation than frame, since the operation which leads to the
+	// last frame's cell has not been chosen yet.
+	type frame struct{ i, j, next int }
+	stack := []frame{{len(m.source), len(m.target), 0}}
+	var reversed []Operation
+	order := m.opOrder()
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		if (f.i > 0 || f.j > 0) && f.next < len(order) {
+			opType := order[f.next]
+			f.next++
+			if op, i, j, ok := m.stepType(opType, f.i, f.j); ok {
+				reversed = append(reversed, op)
+				stack = append(stack, frame{i, j, 0})
+			}
+			continue
+		}
+
+		if f.i == 0 && f.j == 0 {
+			if len(paths) == maxPaths {
+				return paths, true
+			}
+			ops := make([]Operation, len(reversed))
+			for k, op := range reversed {
+				ops[len(ops)-1-k] = op
+			}
+			m.fillResults(ops, true)
+			paths = append(paths, ops)
+		}
+		stack = stack[:len(stack)-1]
+		if len(reversed) > 0 {
+			reversed = reversed[:len(reversed)-1]
+		}
+	}
+	return paths, false
+}
+
+// NetLengthChange returns the number of insertions minus the number of
+// removals in the minimal list of edit operations, which is positive if the
+// target string is an expansion of the source, and negative if it is a
+// contraction. It always equals the difference in length (in runes) between
+// the target and source strings, but is derived from the operations, so it
+// can be used as a consistency check. If the target string cannot be reached
+// within the limits on the number of operations (see MaxInserts), there are
+// no operations to derive it from, so it returns the difference in length
+// directly.
+func (m *Matrix) NetLengthChange() int {
+	if m.infeasible() {
+		return len(m.target) - len(m.source)
+	}
+	change := 0
+	m.walk(len(m.source), len(m.target), func(op Operation) bool {
+		switch op.Type {
+		case Insert:
+			change++
+		case Remove:
+			change--
+		}
+		return true
+	})
+	return change
+}
+
+// SubstitutionRate returns the fraction of the edits (i.e. the operations
+// other than keeps) in the minimal list of edit operations which are swaps,
+// which distinguishes substitution-heavy differences (e.g. typos made by
+// hitting the wrong key) from insertion- and deletion-heavy ones. It returns
+// 0 if there are no edits, including if the target string cannot be reached
+// within the limits on the number of operations (see MaxInserts).
+func (m *Matrix) SubstitutionRate() float64 {
+	if m.infeasible() {
+		return 0
+	}
+	var swaps, edits int
+	m.walk(len(m.source), len(m.target), func(op Operation) bool {
+		switch op.Type {
+		case Swap:
+			swaps++
+			edits++
+		case Insert, Remove:
+			edits++
+		}
+		return true
+	})
+	if edits == 0 {
+		return 0
+	}
+	return float64(swaps) / float64(edits)
+}
+
+// SourceEditDensity returns, for each character of the source string (indexed
+// in runes), the number of edits in the minimal list of edit operations which
+// are attributed to it - e.g. for the intensity of per-character highlighting
+// in a heatmap. A removal or a swap is attributed to the character it removes
+// or replaces, so it contributes at most 1 to each character. An insertion is
+// attributed to the source character immediately before the point at which
+// it is inserted, or to the first source character if it is inserted before
+// all of them, so a run of insertions accumulates on a single character. If the
+// source string is empty, there are no characters to attribute insertions to,
+// and the result is empty. If the target string cannot be reached within the
+// limits on the number of operations (see MaxInserts), there are no
+// operations to attribute, and the result is nil.
+func (m *Matrix) SourceEditDensity() []int {
+	if m.infeasible() {
+		return nil
+	}
+	density := make([]int, len(m.source))
+	if len(m.source) == 0 {
+		return density
+	}
+	i := 0 // Number of source characters consumed so far
+	m.walk(len(m.source), len(m.target), func(op Operation) bool {
+		switch op.Type {
+		case Insert:
+			if i == 0 {
+				density[0]++
+			} else {
+				density[i-1]++
+			}
+		case Remove, Swap:
+			density[i]++
+			i++
+		case Keep:
+			i++
+		}
+		return true
+	})
+	return density
+}
+
+// Explain returns a human-readable summary of the minimal list of edit
+// operations, suitable for logging, e.g. "3 edits: 1 substitution (h→a), 1
+// deletion (o), 1 insertion (o)". The characters involved in each kind of edit
+// are listed in the order in which the edits are made, and kinds of edit which
+// were not needed are left out. If the strings are the same, it returns "no
+// edits", and if the target string cannot be reached within the limits on the
+// number of operations (see MaxInserts), it returns "infeasible".
+func (m *Matrix) Explain() string {
+	if m.infeasible() {
+		return "infeasible"
+	}
+	var swaps, removes, inserts []string
+	m.walk(len(m.source), len(m.target), func(op Operation) bool {
+		switch op.Type {
+		case Swap:
+			swaps = append(swaps, string(op.FromChar)+"→"+string(op.Char))
+		case Remove:
+			removes = append(removes, string(op.Char))
+		case Insert:
+			inserts = append(inserts, string(op.Char))
+		}
+		return true
+	})
+
+	edits := len(swaps) + len(removes) + len(inserts)
+	if edits == 0 {
+		return "no edits"
+	}
+	var parts []string
+	for _, kind := range []struct {
+		name  string
+		chars []string
+	}{
+		{"substitution", swaps},
+		{"deletion", removes},
+		{"insertion", inserts},
+	} {
+		if len(kind.chars) > 0 {
+			parts = append(parts, fmt.Sprintf("%s (%s)", plural(len(kind.chars), kind.name), strings.Join(kind.chars, ", ")))
+		}
+	}
+	return plural(edits, "edit") + ": " + strings.Join(parts, ", ")
+}
+
+// plural returns the count followed by the noun, which is made plural if the
+// count is not one.
+func plural(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// Relationship classifies how the target string relates to the source
+// string, according to the types of operation in the minimal list of edit
+// operations.
+type Relationship int8
+
+const (
+	// Identical means that there are no edits: every operation is a keep.
+	Identical Relationship = iota
+
+	// Insertion means that the target string is the source string with
+	// characters inserted - e.g. appended, or prefixed - with no removals or
+	// swaps.
+	Insertion
+
+	// Deletion means that the target string is the source string with
+	// characters removed, with no insertions or swaps.
+	Deletion
+
+	// Mixed means that the edits include swaps, or both insertions and
+	// removals.
+	Mixed
+
+	// Infeasible means that there are no edit operations, because the
+	// target string cannot be reached within the limits on the number of
+	// operations (see MaxInserts).
+	Infeasible
+)
+
+// String returns the string representation of a relationship.
+func (r Relationship) String() string {
+	switch r {
+	case Identical:
+		return "identical"
+	case Insertion:
+		return "insertion"
+	case Deletion:
+		return "deletion"
+	case Mixed:
+		return "mixed"
+	case Infeasible:
+		return "infeasible"
+	default:
+		return "invalid"
+	}
+}
+
+// Relationship classifies the relationship between the source and target
+// strings, according to the types of operation in the minimal list of edit
+// operations - e.g. so that callers can special-case strings which have only
+// been appended to. If the target string cannot be reached within the limits
+// on the number of operations (see MaxInserts), it returns Infeasible.
+func (m *Matrix) Relationship() Relationship {
+	if m.infeasible() {
+		return Infeasible
+	}
+	var inserts, removes, mixed bool
+	m.walk(len(m.source), len(m.target), func(op Operation) bool {
+		switch op.Type {
+		case Insert:
+			inserts = true
+		case Remove:
+			removes = true
+		case Swap:
+			mixed = true
+		}
+		mixed = mixed || (inserts && removes)
+		return !mixed // The relationship can't change once it is mixed
+	})
+	switch {
+	case mixed:
+		return Mixed
+	case inserts:
+		return Insertion
+	case removes:
+		return Deletion
+	default:
+		return Identical
+	}
+}
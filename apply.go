@@ -0,0 +1,193 @@
+package levenshtein
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Apply performs a list of edit operations on the source string, returning
+// the result. Applying the operations returned by Operations to the source
+// string yields the target string. An error is returned if an operation is
+// out of range, or if the character it expects to remove or keep does not
+// match the character at its index.
+func Apply(source string, ops []Operation) (string, error) {
+	result := []rune(source)
+	for n, op := range ops {
+		var err error
+		if result, err = applyOp(result, op); err != nil {
+			return "", fmt.Errorf("operation %d (%s): %w", n, op.Type, err)
+		}
+	}
+	return string(result), nil
+}
+
+// applyOp performs a single edit operation on the given runes.
+func applyOp(runes []rune, op Operation) ([]rune, error) {
+	if op.Type == Insert {
+		if op.Index < 0 || op.Index > len(runes) {
+			return nil, fmt.Errorf("index %d out of range [0,%d]", op.Index, len(runes))
+		}
+		runes = append(runes, 0)
+		copy(runes[op.Index+1:], runes[op.Index:])
+		runes[op.Index] = op.Char
+		return runes, nil
+	}
+
+	if op.Index < 0 || op.Index >= len(runes) {
+		return nil, fmt.Errorf("index %d out of range [0,%d)", op.Index, len(runes))
+	}
+	switch op.Type {
+	case Remove:
+		if runes[op.Index] != op.Char {
+			return nil, fmt.Errorf("expected %q at index %d, found %q", op.Char, op.Index, runes[op.Index])
+		}
+		return append(runes[:op.Index], runes[op.Index+1:]...), nil
+	case Swap:
+		runes[op.Index] = op.Char
+		return runes, nil
+	case Keep:
+		if runes[op.Index] != op.Char {
+			return nil, fmt.Errorf("expected %q at index %d, found %q", op.Char, op.Index, runes[op.Index])
+		}
+		return runes, nil
+	default:
+		return nil, fmt.Errorf("invalid operation type %d", op.Type)
+	}
+}
+
+// ApplyTo performs a list of edit operations on the source string, streaming
+// the result to the writer as it goes rather than building it in memory. The
+// operations must be ordered by the position at which they occur, as they are
+// when returned by Operations. Any characters of the source string not
+// covered by an operation are copied to the writer unchanged.
+func ApplyTo(w io.Writer, source string, ops []Operation) error {
+	bw := bufio.NewWriter(w)
+	src := strings.NewReader(source)
+	written := 0 // Number of runes written so far
+
+	// copyUntil copies runes from the source to the writer until the given
+	// number of runes have been written.
+	copyUntil := func(index int) error {
+		for ; written < index; written++ {
+			r, _, err := src.ReadRune()
+			if err != nil {
+				return fmt.Errorf("index %d out of range", index)
+			}
+			if _, err := bw.WriteRune(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for n, op := range ops {
+		if op.Index < written {
+			return fmt.Errorf("operation %d (%s): index %d precedes previous operation", n, op.Type, op.Index)
+		}
+		if err := copyUntil(op.Index); err != nil {
+			return fmt.Errorf("operation %d (%s): %w", n, op.Type, err)
+		}
+
+		// Consume the source character affected by the operation, if any
+		if op.Type != Insert {
+			r, _, err := src.ReadRune()
+			if err != nil {
+				return fmt.Errorf("operation %d (%s): index %d out of range", n, op.Type, op.Index)
+			}
+			if (op.Type == Remove || op.Type == Keep) && r != op.Char {
+				return fmt.Errorf("operation %d (%s): expected %q at index %d, found %q", n, op.Type, op.Char, op.Index, r)
+			}
+		}
+
+		switch op.Type {
+		case Insert, Swap, Keep:
+			if _, err := bw.WriteRune(op.Char); err != nil {
+				return err
+			}
+			written++
+		case Remove:
+		default:
+			return fmt.Errorf("operation %d: invalid operation type %d", n, op.Type)
+		}
+	}
+
+	if _, err := src.WriteTo(bw); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// Invert returns the inverse of a list of edit operations: a list which
+// transforms the target string back into the source string, e.g. for undo.
+// Insertions become removals and vice versa, swaps are reversed (using
+// FromChar), and the order of the operations is reversed. Only the Type,
+// Char, FromChar, IsCaseOnly, and Index fields of the inverted operations are
+// set.
+func Invert(ops []Operation) []Operation {
+	inverted := make([]Operation, len(ops))
+	for n, op := range ops {
+		inv := Operation{
+			Type:  op.Type,
+			Char:  op.Char,
+			Index: op.Index,
+		}
+		switch op.Type {
+		case Insert:
+			inv.Type = Remove
+		case Remove:
+			inv.Type = Insert
+		case Swap:
+			inv.Char, inv.FromChar = op.FromChar, op.Char
+			inv.IsCaseOnly = op.IsCaseOnly
+		}
+		inverted[len(ops)-1-n] = inv
+	}
+	return inverted
+}
+
+// VerifyResults checks that the Result of each operation in the list is
+// exactly one edit away from the Result of the previous operation - i.e. that
+// performing the operation on the previous result yields its own result (or
+// the same result, for keeps). The first operation is checked against the
+// string obtained by undoing it. It is an internal consistency check, mainly
+// intended for tests, and requires the operations to have been built with
+// ResultFull (the default).
+func VerifyResults(ops []Operation) error {
+	var prev []rune
+	for n, op := range ops {
+		if n == 0 {
+			var err error
+			if prev, err = undoOp([]rune(op.Result), op); err != nil {
+				return fmt.Errorf("operation %d (%s): %w", n, op.Type, err)
+			}
+		}
+		if op.Type == Swap && op.Index >= 0 && op.Index < len(prev) && prev[op.Index] != op.FromChar {
+			return fmt.Errorf("operation %d (%s): expected %q at index %d, found %q", n, op.Type, op.FromChar, op.Index, prev[op.Index])
+		}
+		next, err := applyOp(prev, op)
+		if err != nil {
+			return fmt.Errorf("operation %d (%s): %w", n, op.Type, err)
+		}
+		if string(next) != op.Result {
+			return fmt.Errorf("operation %d (%s): result %q, expected %q", n, op.Type, op.Result, string(next))
+		}
+		prev = []rune(op.Result)
+	}
+	return nil
+}
+
+// undoOp reverses a single edit operation on the given runes, returning the
+// runes as they were before it was performed.
+func undoOp(runes []rune, op Operation) ([]rune, error) {
+	switch op.Type {
+	case Insert:
+		op.Type = Remove
+	case Remove:
+		op.Type = Insert
+	case Swap:
+		op.Char = op.FromChar
+	}
+	return applyOp(runes, op)
+}
@@ -0,0 +1,160 @@
+package levenshtein_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func TestApplyTo(t *testing.T) {
+	for _, test := range []struct {
+		source string
+		ops    []levenshtein.Operation
+	}{
+		{"horse", levenshtein.Operations("horse", "arose")},
+		{"kitten", levenshtein.Operations("kitten", "sitting")},
+		{"", levenshtein.Operations("", "abc")},
+		{"abc", levenshtein.Operations("abc", "")},
+		{"héllo", levenshtein.Operations("héllo", "hëllø wörld")},
+		{"untouched", nil},
+		{"partial", []levenshtein.Operation{
+			{Type: levenshtein.Swap, Char: 'P', Index: 0},
+			{Type: levenshtein.Insert, Char: '-', Index: 4},
+		}},
+	} {
+		want, err := levenshtein.Apply(test.source, test.ops)
+		if err != nil {
+			t.Fatalf("Apply(%q): %v", test.source, err)
+		}
+
+		var buf bytes.Buffer
+		if err := levenshtein.ApplyTo(&buf, test.source, test.ops); err != nil {
+			t.Fatalf("ApplyTo(%q): %v", test.source, err)
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("ApplyTo(%q) wrote %q, want %q", test.source, got, want)
+		}
+	}
+}
+
+func TestApplyToInvalid(t *testing.T) {
+	for _, test := range []struct {
+		source string
+		ops    []levenshtein.Operation
+	}{
+		{"abc", []levenshtein.Operation{{Type: levenshtein.Keep, Char: 'x', Index: 0}}},
+		{"abc", []levenshtein.Operation{{Type: levenshtein.Remove, Char: 'a', Index: 3}}},
+		{"abc", []levenshtein.Operation{
+			{Type: levenshtein.Keep, Char: 'b', Index: 1},
+			{Type: levenshtein.Keep, Char: 'a', Index: 0},
+		}},
+	} {
+		var buf bytes.Buffer
+		if err := levenshtein.ApplyTo(&buf, test.source, test.ops); err == nil {
+			t.Errorf("ApplyTo(%q, %v) succeeded, want error", test.source, test.ops)
+		}
+	}
+}
+
+func TestVerifyResults(t *testing.T) {
+	for _, test := range []struct {
+		source, target string
+		options        []levenshtein.Option
+	}{
+		{"horse", "arose", nil},
+		{"kitten", "sitting", nil},
+		{"", "abc", nil},
+		{"abc", "", nil},
+		{"", "", nil},
+		{"héllo", "hëllø wörld", nil},
+		{"horse", "arose", []levenshtein.Option{levenshtein.PreferOperation(levenshtein.Swap)}},
+		{"ab", "axyzb", []levenshtein.Option{levenshtein.SetAffineGap(3, 1)}},
+		{"rnodern", "modern", []levenshtein.Option{levenshtein.ConfusionPairs(map[string]string{"rn": "m"}, 0)}},
+	} {
+		ops := levenshtein.Operations(test.source, test.target, test.options...)
+		if err := levenshtein.VerifyResults(ops); err != nil {
+			t.Errorf("VerifyResults(Operations(%q, %q)): %v", test.source, test.target, err)
+		}
+		if err := levenshtein.VerifyResults(levenshtein.Build(test.source, test.target, test.options...).OperationsTargetOrder()); err != nil {
+			t.Errorf("VerifyResults(OperationsTargetOrder(%q, %q)): %v", test.source, test.target, err)
+		}
+	}
+
+	// Operations: swap h→a, remove o, keep r, insert o, keep s, keep e
+	corrupt := func(f func(ops []levenshtein.Operation)) []levenshtein.Operation {
+		ops := levenshtein.Operations("horse", "arose")
+		f(ops)
+		return ops
+	}
+	for _, test := range []struct {
+		name string
+		ops  []levenshtein.Operation
+	}{
+		{"wrong result", corrupt(func(ops []levenshtein.Operation) { ops[3].Result = "arsoe" })},
+		{"two edits", corrupt(func(ops []levenshtein.Operation) { ops[1].Result = "ase" })},
+		{"changed keep", corrupt(func(ops []levenshtein.Operation) { ops[2].Result = "arze" })},
+		{"wrong index", corrupt(func(ops []levenshtein.Operation) { ops[3].Index = 3 })},
+		{"wrong swap", corrupt(func(ops []levenshtein.Operation) {
+			ops[1] = levenshtein.Operation{Type: levenshtein.Swap, Char: 'a', FromChar: 'x', Index: 1, Result: "aarse"}
+		})},
+		{"wrong first", corrupt(func(ops []levenshtein.Operation) { ops[0].Result = "borse" })},
+		{"missing operation", corrupt(func(ops []levenshtein.Operation) { copy(ops[1:], ops[2:]) })},
+	} {
+		if err := levenshtein.VerifyResults(test.ops); err == nil {
+			t.Errorf("VerifyResults(%s) succeeded, want error", test.name)
+		}
+	}
+}
+
+func TestApplyTuples(t *testing.T) {
+	for _, test := range []struct{ source, target string }{
+		{"horse", "arose"},
+		{"kitten", "sitting"},
+		{"", "abc"},
+		{"abc", ""},
+		{"", ""},
+		{"héllo", "hëllø wörld"},
+	} {
+		tuples := levenshtein.Build(test.source, test.target).Tuples()
+		if len(tuples) != len(levenshtein.Operations(test.source, test.target)) {
+			t.Errorf("Tuples(%q, %q) has %d tuples, expected one per operation", test.source, test.target, len(tuples))
+		}
+		result, err := levenshtein.ApplyTuples(test.source, tuples)
+		if err != nil {
+			t.Errorf("ApplyTuples(%q, Tuples(%q, %q)): %v", test.source, test.source, test.target, err)
+		} else if result != test.target {
+			t.Errorf("ApplyTuples(%q, Tuples(%q, %q)) = %q, expected %q", test.source, test.source, test.target, result, test.target)
+		}
+	}
+
+	tuples := levenshtein.Build("horse", "arose").Tuples()
+	expected := []levenshtein.OpTuple{
+		{levenshtein.Swap, 'a'},
+		{levenshtein.Remove, 'o'},
+		{levenshtein.Keep, 'r'},
+		{levenshtein.Insert, 'o'},
+		{levenshtein.Keep, 's'},
+		{levenshtein.Keep, 'e'},
+	}
+	if !reflect.DeepEqual(tuples, expected) {
+		t.Errorf("Tuples(\"horse\", \"arose\") = %v, expected %v", tuples, expected)
+	}
+
+	for _, test := range []struct {
+		name   string
+		source string
+		tuples []levenshtein.OpTuple
+	}{
+		{"wrong keep", "horse", append([]levenshtein.OpTuple{{levenshtein.Keep, 'x'}}, tuples[1:]...)},
+		{"wrong remove", "horse", append(append([]levenshtein.OpTuple{}, tuples[:1]...), append([]levenshtein.OpTuple{{levenshtein.Remove, 'x'}}, tuples[2:]...)...)},
+		{"too few", "horse", tuples[:5]},
+		{"too many", "hors", tuples},
+		{"invalid type", "a", []levenshtein.OpTuple{{levenshtein.OpType(9), 'a'}}},
+	} {
+		if _, err := levenshtein.ApplyTuples(test.source, test.tuples); err == nil {
+			t.Errorf("ApplyTuples(%s) succeeded, want error", test.name)
+		}
+	}
+}
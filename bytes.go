@@ -0,0 +1,90 @@
+package levenshtein
+
+import "sync"
+
+// bytesPool holds workspaces for DistanceBytesWithin.
+var bytesPool sync.Pool
+
+// DistanceBytesWithin calculates the edit distance between two byte slices,
+// treating each byte as a character and using the default costs, and reports
+// whether it is at most max. The byte slices are compared directly, without
+// converting them to strings or decoding them as UTF-8, which makes this
+// suitable for diffing binary data. Only the cells of the matrix within max of
+// the diagonal are calculated, and the calculation stops as soon as it is
+// clear that the distance exceeds max, in which case the returned distance is
+// max+1 rather than the exact distance. A negative max is treated as -1, so no
+// distance is ever within it.
+func DistanceBytesWithin(a, b []byte, max int) (int, bool) {
+	if max < 0 {
+		return 0, false
+	}
+	if max > len(a)+len(b) {
+		// The distance is never greater than the total length, so a larger
+		// max makes no difference, except to overflow the band
+		max = len(a) + len(b)
+	}
+	exceeded := max + 1
+	if len(a)-len(b) > max || len(b)-len(a) > max {
+		return exceeded, false
+	}
+
+	rows := getRows(&bytesPool, len(b)+1)
+	defer bytesPool.Put(rows)
+	prev, row := (*rows)[:len(b)+1], (*rows)[len(b)+1:]
+
+	// Cells outside of the band are never within max, so they are treated
+	// as if they exceed it
+	for j := range prev {
+		prev[j] = j
+		if j > max {
+			prev[j] = exceeded
+		}
+	}
+	for i := 1; i <= len(a); i++ {
+		lo, hi := i-max, i+max
+		if lo < 1 {
+			lo = 1
+		}
+		if hi > len(b) {
+			hi = len(b)
+		}
+
+		rowMin := exceeded
+		if i <= max {
+			row[0] = i
+			rowMin = i
+		} else {
+			row[lo-1] = exceeded
+		}
+		for j := lo; j <= hi; j++ {
+			val := prev[j-1]
+			if a[i-1] != b[j-1] {
+				val++
+			}
+			if prev[j]+1 < val {
+				val = prev[j] + 1
+			}
+			if row[j-1]+1 < val {
+				val = row[j-1] + 1
+			}
+			if val > exceeded {
+				val = exceeded
+			}
+			row[j] = val
+			if val < rowMin {
+				rowMin = val
+			}
+		}
+		if hi < len(b) {
+			row[hi+1] = exceeded
+		}
+
+		if rowMin > max {
+			return exceeded, false
+		}
+		prev, row = row, prev
+	}
+
+	dist := prev[len(b)]
+	return dist, dist <= max
+}
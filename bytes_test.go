@@ -0,0 +1,82 @@
+package levenshtein_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func FuzzDistanceBytesWithin(f *testing.F) {
+	f.Add("horse", "arose", 3)
+	f.Add("", "", 0)
+	f.Add("", "abc", 2)
+	f.Add("kitten", "sitting", 10)
+	f.Add("abcdef", "fedcba", 4)
+	f.Fuzz(func(t *testing.T, a, b string, max int) {
+		// Decoding as runes only matches the byte-wise comparison for ASCII
+		a, b = ascii(a), ascii(b)
+		max %= 64
+
+		want := levenshtein.Distance(a, b)
+		dist, ok := levenshtein.DistanceBytesWithin([]byte(a), []byte(b), max)
+		if ok != (max >= 0 && want <= max) {
+			t.Fatalf("DistanceBytesWithin(%q, %q, %d) within = %t, but distance is %d", a, b, max, ok, want)
+		}
+		if ok && dist != want {
+			t.Fatalf("DistanceBytesWithin(%q, %q, %d) = %d, want %d", a, b, max, dist, want)
+		}
+	})
+}
+
+func TestDistanceBytesWithinLargeMax(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		dist int
+	}{
+		{"kitten", "sitting", 3},
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "xyz", 3},
+	} {
+		if dist, ok := levenshtein.DistanceBytesWithin([]byte(test.a), []byte(test.b), math.MaxInt); !ok || dist != test.dist {
+			t.Errorf("DistanceBytesWithin(%q, %q, math.MaxInt) = %d, %t, want %d, true", test.a, test.b, dist, ok, test.dist)
+		}
+	}
+}
+
+func ascii(s string) string {
+	b := []byte(s)
+	for i := range b {
+		b[i] &= 0x7f
+	}
+	return string(b)
+}
+
+var (
+	largeA = bytes.Repeat([]byte("the quick brown fox jumps over the lazy dog "), 100)
+	largeB = bytes.Replace(largeA, []byte("fox"), []byte("cat"), 10)
+)
+
+func BenchmarkDistanceBytesWithin(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		levenshtein.DistanceBytesWithin(largeA, largeB, 40)
+	}
+}
+
+func BenchmarkDistanceBytesWithinExceeded(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		levenshtein.DistanceBytesWithin(largeA, largeB, 10)
+	}
+}
+
+func BenchmarkDistanceLarge(b *testing.B) {
+	b.ReportAllocs()
+	a, c := string(largeA), string(largeB)
+	for i := 0; i < b.N; i++ {
+		levenshtein.Distance(a, c)
+	}
+}
@@ -0,0 +1,72 @@
+package levenshtein
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CacheKey returns a key which identifies the edit distance between the two
+// strings under the given options, for memoizing distance results. The key
+// encodes the strings after any transformations (e.g. IgnoreCase) have been
+// applied, so pairs which are equal after normalization share a key, along
+// with the cost configuration, so that different costs do not collide. If
+// the costs are symmetric (see Matrix.Symmetric), the pair is sorted, so the
+// key does not depend on the order of the arguments.
+//
+// Swap cost functions cannot be compared, so the key only records whether
+// one was provided: callers which use SetSwapCostFunc with different
+// functions should add their own identifier to the key. Cost models are
+// encoded using their type and fields, and are treated as asymmetric.
+func CacheKey(a, b string, options ...Option) string {
+	m := newConfig(options)
+	source, target := string(m.prepare(a)), string(m.prepare(b))
+	if m.Symmetric() && target < source {
+		source, target = target, source
+	}
+
+	var key strings.Builder
+	fmt.Fprintf(&key, "i%d r%d s%d f%d", m.insertCost, m.removeCost, m.swapCost, m.firstCost)
+	if m.swapFunc != nil {
+		key.WriteString(" sf")
+	}
+	if m.markCostOK {
+		fmt.Fprintf(&key, " m%d", m.markCost)
+	}
+	if m.caseCostOK {
+		fmt.Fprintf(&key, " c%d", m.caseCost)
+	}
+	if m.wildcardOK {
+		fmt.Fprintf(&key, " w%d", m.wildcard)
+	}
+	if m.affine {
+		fmt.Fprintf(&key, " g%d,%d", m.gapOpen, m.gapExtend)
+	}
+	if m.maxInserts >= 0 || m.maxRemoves >= 0 || m.maxSwaps >= 0 {
+		fmt.Fprintf(&key, " l%d,%d,%d", m.maxInserts, m.maxRemoves, m.maxSwaps)
+	}
+	if m.bonus != 0 {
+		fmt.Fprintf(&key, " b%d", m.bonus)
+	}
+	if m.weights != nil {
+		key.WriteString(" p")
+		for _, w := range m.weights {
+			key.WriteString(strconv.FormatFloat(w, 'g', -1, 64) + ",")
+		}
+	}
+	if m.confusions != nil {
+		// The order of the pairs depends on map iteration, so sort them
+		pairs := make([]string, 0, len(m.confusions))
+		for _, c := range m.confusions {
+			pairs = append(pairs, strconv.Quote(string(c.from))+">"+strconv.Quote(string(c.to)))
+		}
+		sort.Strings(pairs)
+		fmt.Fprintf(&key, " x%d[%s]", m.confCost, strings.Join(pairs, ","))
+	}
+	if m.costs != nil {
+		fmt.Fprintf(&key, " %T%+v", m.costs, m.costs)
+	}
+	key.WriteString(" " + strconv.Quote(source) + " " + strconv.Quote(target))
+	return key.String()
+}
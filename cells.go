@@ -0,0 +1,147 @@
+package levenshtein
+
+import "math"
+
+// newCells returns a matrix of integers of the given type, with one row for
+// each character of the source string (plus one), and one column for each
+// character of the target string (plus one). The rows share a single backing
+// array.
+func newCells[T int16 | int32](source, target []rune) [][]T {
+	cols := len(target) + 1
+	backing := make([]T, (len(source)+1)*cols)
+	cells := make([][]T, len(source)+1)
+	for i := range cells {
+		cells[i] = backing[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+	return cells
+}
+
+// allocate allocates the cells of the matrix for its source and target
+// strings. To save memory, the cells are stored in the narrowest integer type
+// which can hold every value they could possibly take, given the lengths of
+// the strings and the costs. The narrow types require the matrix to be filled
+// using a separate workspace, so they are only used when the costs are known
+// to be non-negative and each cell depends only on its neighbours.
+func (m *Matrix) allocate() {
+	m.matrix, m.matrix32, m.matrix16 = nil, nil, nil
+	switch bound := m.cellBound(); {
+	case bound < 0 || bound > math.MaxInt32:
+		m.matrix = newMatrix(m.source, m.target)
+	case bound > math.MaxInt16:
+		m.matrix32 = newCells[int32](m.source, m.target)
+	default:
+		m.matrix16 = newCells[int16](m.source, m.target)
+	}
+}
+
+// cellBound returns an upper bound on the values of the cells of the matrix,
+// or -1 if no bound can be determined (e.g. because the costs may be negative,
+// or because they are determined by a function).
+func (m *Matrix) cellBound() int {
+	if m.affine || m.limited() || m.costs != nil || m.swapFunc != nil || m.confusions != nil || m.bonus != 0 {
+		return -1
+	}
+	if m.insertCost < 0 || m.removeCost < 0 || m.swapCost < 0 || m.firstCost < 0 ||
+		(m.markCostOK && m.markCost < 0) || (m.caseCostOK && m.caseCost < 0) {
+		return -1
+	}
+	for _, w := range m.weights {
+		if w < 0 {
+			return -1
+		}
+	}
+
+	// Every cell can be reached by inserting characters of the target string
+	// along the first row, and then removing characters of the source string
+	// down the column, so no cell can exceed the cost of inserting and
+	// removing every character
+	bound := 0
+	for j := 1; j <= len(m.target) && bound <= math.MaxInt32; j++ {
+		bound += m.insert(j)
+	}
+	for i := 1; i <= len(m.source) && bound <= math.MaxInt32; i++ {
+		bound += m.remove(i)
+	}
+	return bound
+}
+
+// at returns the value of cell (i, j) of the matrix.
+func (m *Matrix) at(i, j int) int {
+	switch {
+	case m.matrix16 != nil:
+		return int(m.matrix16[i][j])
+	case m.matrix32 != nil:
+		return int(m.matrix32[i][j])
+	default:
+		return m.matrix[i][j]
+	}
+}
+
+// set sets the value of cell (i, j) of the matrix.
+func (m *Matrix) set(i, j, val int) {
+	switch {
+	case m.matrix16 != nil:
+		m.matrix16[i][j] = int16(val)
+	case m.matrix32 != nil:
+		m.matrix32[i][j] = int32(val)
+	default:
+		m.matrix[i][j] = val
+	}
+}
+
+// storeRow copies the values of a workspace row into row i of the matrix.
+func (m *Matrix) storeRow(i int, row []int) {
+	switch {
+	case m.matrix16 != nil:
+		for j, val := range row {
+			m.matrix16[i][j] = int16(val)
+		}
+	case m.matrix32 != nil:
+		for j, val := range row {
+			m.matrix32[i][j] = int32(val)
+		}
+	default:
+		copy(m.matrix[i], row)
+	}
+}
+
+// exceeds reports whether a row of the matrix whose minimum is rowMin rules out
+// a distance of at most max (unless max is negative). Later rows can only be
+// cheaper than earlier ones if keeping characters can have a negative cost,
+// so rows never rule anything out with WordBoundaryBonus.
+func (m *Matrix) exceeds(rowMin, max int) bool {
+	return max >= 0 && rowMin > max && m.bonus == 0
+}
+
+// fillRows fills the matrix row by row, giving up once the minimum of a row
+// is greater than max (unless max is negative). It returns the minimum of the
+// last row which was filled, and whether it is within max.
+func (m *Matrix) fillRows(max int) (int, bool) {
+	if m.matrix != nil {
+		// The rows can be filled in place
+		m.fillFirstRow(m.matrix[0])
+		rowMin := 0
+		for i := 1; i <= len(m.source); i++ {
+			rowMin = m.fillRow(i, m.matrix[i-1], m.matrix[i])
+			if m.exceeds(rowMin, max) {
+				return rowMin, false
+			}
+		}
+		return rowMin, true
+	}
+
+	rows := make([]int, 2*(len(m.target)+1))
+	prev, row := rows[:len(m.target)+1], rows[len(m.target)+1:]
+	m.fillFirstRow(prev)
+	m.storeRow(0, prev)
+	rowMin := 0
+	for i := 1; i <= len(m.source); i++ {
+		rowMin = m.fillRow(i, prev, row)
+		m.storeRow(i, row)
+		if m.exceeds(rowMin, max) {
+			return rowMin, false
+		}
+		prev, row = row, prev
+	}
+	return rowMin, true
+}
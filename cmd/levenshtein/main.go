@@ -1,32 +1,132 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/nathanjcochran/levenshtein"
 )
 
 func main() {
-	if len(os.Args) < 3 {
-		fmt.Printf("%s: missing required arguments\n", os.Args[0])
-		usage()
-	} else if len(os.Args) > 3 {
-		fmt.Printf("%s: too many arguments provided\n", os.Args[0])
-		usage()
+	os.Exit(run(os.Args, os.Stdout, os.Stderr))
+}
+
+// ArgErrorKind identifies the kind of problem with the command line
+// arguments.
+type ArgErrorKind int
+
+const (
+	// MissingArgs indicates that fewer than two strings were provided.
+	MissingArgs ArgErrorKind = iota
+	// TooManyArgs indicates that more than two strings were provided.
+	TooManyArgs
+	// InvalidFlag indicates that a flag was unknown, or had an invalid value.
+	InvalidFlag
+)
+
+// ArgError describes a problem with the command line arguments.
+type ArgError struct {
+	Kind ArgErrorKind
+	Err  error // The underlying error, for invalid flags
+}
+
+// Error implements the error interface.
+func (e *ArgError) Error() string {
+	switch e.Kind {
+	case MissingArgs:
+		return "missing required arguments"
+	case TooManyArgs:
+		return "too many arguments provided"
+	default:
+		return fmt.Sprintf("invalid flag: %s", e.Err)
 	}
+}
+
+// Unwrap returns the underlying error.
+func (e *ArgError) Unwrap() error {
+	return e.Err
+}
 
-	matrix := levenshtein.Build(os.Args[1], os.Args[2])
+// options holds the parsed command line arguments.
+type options struct {
+	files          bool
+	source, target string
+}
 
-	fmt.Printf("Matrix:\n%s\n\n", matrix)
-	fmt.Printf("Edit distance: %d\n", matrix.Distance())
-	fmt.Printf("Operations:\n")
+// parseArgs parses the command line arguments (excluding the program name).
+// It returns an *ArgError if they are invalid, or flag.ErrHelp if help was
+// requested.
+func parseArgs(name string, args []string) (options, error) {
+	var opts options
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.BoolVar(&opts.files, "files", false, "compare the lines of two files, rather than two strings")
+	if err := flags.Parse(args); err == flag.ErrHelp {
+		return opts, err
+	} else if err != nil {
+		return opts, &ArgError{Kind: InvalidFlag, Err: err}
+	}
+
+	if flags.NArg() < 2 {
+		return opts, &ArgError{Kind: MissingArgs}
+	} else if flags.NArg() > 2 {
+		return opts, &ArgError{Kind: TooManyArgs}
+	}
+	opts.source, opts.target = flags.Arg(0), flags.Arg(1)
+	return opts, nil
+}
+
+// run runs the command with the given arguments (including the program name),
+// and returns the exit code. Problems with the arguments are reported on
+// stderr, along with the usage, and result in exit code 2.
+func run(args []string, stdout, stderr io.Writer) int {
+	opts, err := parseArgs(args[0], args[1:])
+	if err == flag.ErrHelp {
+		usage(stdout, args[0])
+		return 0
+	} else if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", args[0], err)
+		return usage(stderr, args[0])
+	}
+
+	if opts.files {
+		return compareFiles(opts.source, opts.target, stdout, stderr)
+	}
+
+	matrix := levenshtein.Build(opts.source, opts.target)
+
+	fmt.Fprintf(stdout, "Matrix:\n%s\n\n", matrix)
+	fmt.Fprintf(stdout, "Edit distance: %d\n", matrix.Distance())
+	fmt.Fprintf(stdout, "Operations:\n")
 	for _, op := range matrix.Operations() {
-		fmt.Printf(" %s\n", op)
+		fmt.Fprintf(stdout, " %s\n", op)
+	}
+	return 0
+}
+
+// compareFiles prints the line-level edit operations required to transform
+// the source file into the target file.
+func compareFiles(sourcePath, targetPath string, stdout, stderr io.Writer) int {
+	source, err := os.ReadFile(sourcePath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error reading source file: %s\n", err)
+		return 1
+	}
+	target, err := os.ReadFile(targetPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error reading target file: %s\n", err)
+		return 1
+	}
+
+	for _, op := range levenshtein.LineOperations(string(source), string(target)) {
+		fmt.Fprintf(stdout, "%s\n", op)
 	}
+	return 0
 }
 
-func usage() {
-	fmt.Printf("Usage: %s <source> <target>\n", os.Args[0])
-	os.Exit(2)
+func usage(w io.Writer, name string) int {
+	fmt.Fprintf(w, "Usage: %s [-files] <source> <target>\n", name)
+	return 2
 }
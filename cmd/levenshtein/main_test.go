@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunFiles(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "a.txt")
+	target := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(source, []byte("one\ntwo\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("one\n2\nthree\nfour\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"levenshtein", "-files", source, target}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, want 0 (stderr: %q)", code, stderr.String())
+	}
+	want := "  keep line 0: one\n" +
+		"  swap line 1: 2\n" +
+		"  keep line 2: three\n" +
+		"insert line 3: four\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestRunFilesMissing(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(source, []byte("one\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"levenshtein", "-files", source, filepath.Join(dir, "missing.txt")}, &stdout, &stderr)
+	if code == 0 {
+		t.Errorf("exit code = 0, want nonzero")
+	}
+	if !strings.Contains(stderr.String(), "missing.txt") {
+		t.Errorf("stderr = %q, want mention of missing file", stderr.String())
+	}
+}
+
+func TestRunArgErrors(t *testing.T) {
+	tests := []struct {
+		args    []string
+		kind    ArgErrorKind
+		message string
+	}{
+		{[]string{"levenshtein"}, MissingArgs, "levenshtein: missing required arguments\n"},
+		{[]string{"levenshtein", "horse"}, MissingArgs, "levenshtein: missing required arguments\n"},
+		{[]string{"levenshtein", "a", "b", "c"}, TooManyArgs, "levenshtein: too many arguments provided\n"},
+		{[]string{"levenshtein", "-files=maybe", "a", "b"}, InvalidFlag, "levenshtein: invalid flag: invalid boolean value \"maybe\" for -files: parse error\n"},
+		{[]string{"levenshtein", "-bogus", "a", "b"}, InvalidFlag, "levenshtein: invalid flag: flag provided but not defined: -bogus\n"},
+	}
+	for _, test := range tests {
+		_, err := parseArgs(test.args[0], test.args[1:])
+		var argErr *ArgError
+		if !errors.As(err, &argErr) || argErr.Kind != test.kind {
+			t.Errorf("parseArgs(%q) error = %v, want kind %d", test.args, err, test.kind)
+		}
+
+		var stdout, stderr bytes.Buffer
+		if code := run(test.args, &stdout, &stderr); code != 2 {
+			t.Errorf("run(%q) exit code = %d, want 2", test.args, code)
+		}
+		if stdout.Len() != 0 {
+			t.Errorf("run(%q) stdout = %q, want empty", test.args, stdout.String())
+		}
+		want := test.message + "Usage: levenshtein [-files] <source> <target>\n"
+		if got := stderr.String(); got != want {
+			t.Errorf("run(%q) stderr = %q, want %q", test.args, got, want)
+		}
+	}
+}
+
+func TestRunHelp(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"levenshtein", "-h"}, &stdout, &stderr); code != 0 {
+		t.Errorf("exit code = %d, want 0", code)
+	}
+	if got, want := stdout.String(), "Usage: levenshtein [-files] <source> <target>\n"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+}
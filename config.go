@@ -0,0 +1,61 @@
+package levenshtein
+
+// Config is an alternative to the functional options, which is convenient
+// when many settings are needed, or when they are determined dynamically.
+// Zero-value fields are ignored, so costs left at zero default to
+// DefaultInsertCost, DefaultRemoveCost and DefaultSwapCost. To use a cost of
+// zero, use the corresponding option instead.
+type Config struct {
+	InsertCost      int
+	RemoveCost      int
+	SwapCost        int
+	SwapCostFunc    func(from, to rune) int
+	IgnoreCase      bool
+	IgnoreCaseASCII bool
+	Loose           bool
+	Transliterate   bool
+	ResultMode      ResultMode
+	Trace           bool
+}
+
+// Options returns the list of options equivalent to the config.
+func (c Config) Options() []Option {
+	options := []Option{}
+	if c.InsertCost != 0 {
+		options = append(options, SetInsertCost(c.InsertCost))
+	}
+	if c.RemoveCost != 0 {
+		options = append(options, SetRemoveCost(c.RemoveCost))
+	}
+	if c.SwapCost != 0 {
+		options = append(options, SetSwapCost(c.SwapCost))
+	}
+	if c.SwapCostFunc != nil {
+		options = append(options, SetSwapCostFunc(c.SwapCostFunc))
+	}
+	if c.IgnoreCase {
+		options = append(options, IgnoreCase())
+	}
+	if c.IgnoreCaseASCII {
+		options = append(options, IgnoreCaseASCII())
+	}
+	if c.Loose {
+		options = append(options, Loose())
+	}
+	if c.Transliterate {
+		options = append(options, Transliterate())
+	}
+	if c.ResultMode != ResultFull {
+		options = append(options, SetResultMode(c.ResultMode))
+	}
+	if c.Trace {
+		options = append(options, WithTrace())
+	}
+	return options
+}
+
+// BuildConfig builds an edit matrix for the two strings using the given
+// config. It is equivalent to: Build(source, target, cfg.Options()...)
+func BuildConfig(source, target string, cfg Config) *Matrix {
+	return Build(source, target, cfg.Options()...)
+}
@@ -0,0 +1,52 @@
+package levenshtein_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func TestBuildConfig(t *testing.T) {
+	tests := []struct {
+		cfg     levenshtein.Config
+		options []levenshtein.Option
+	}{
+		{levenshtein.Config{}, nil},
+		{
+			levenshtein.Config{InsertCost: 2, RemoveCost: 3, SwapCost: 4},
+			[]levenshtein.Option{levenshtein.SetInsertCost(2), levenshtein.SetRemoveCost(3), levenshtein.SetSwapCost(4)},
+		},
+		{
+			levenshtein.Config{SwapCost: 1},
+			[]levenshtein.Option{levenshtein.SetInsertCost(levenshtein.DefaultInsertCost), levenshtein.SetSwapCost(1)},
+		},
+		{levenshtein.Config{IgnoreCase: true}, []levenshtein.Option{levenshtein.IgnoreCase()}},
+		{levenshtein.Config{Loose: true}, []levenshtein.Option{levenshtein.Loose()}},
+		{levenshtein.Config{Transliterate: true}, []levenshtein.Option{levenshtein.Transliterate()}},
+		{
+			levenshtein.Config{ResultMode: levenshtein.ResultNone, Trace: true},
+			[]levenshtein.Option{levenshtein.SetResultMode(levenshtein.ResultNone), levenshtein.WithTrace()},
+		},
+	}
+
+	for _, pair := range [][2]string{{"Café", "CAFFE"}, {"kitten", "sitting"}, {"", "Москва"}} {
+		for _, test := range tests {
+			m1 := levenshtein.BuildConfig(pair[0], pair[1], test.cfg)
+			m2 := levenshtein.Build(pair[0], pair[1], test.options...)
+
+			data1, err1 := m1.MarshalBinary()
+			data2, err2 := m2.MarshalBinary()
+			if err1 != nil || err2 != nil {
+				t.Fatalf("MarshalBinary failed: %v, %v", err1, err2)
+			}
+			if !bytes.Equal(data1, data2) {
+				t.Errorf("BuildConfig(%q, %q, %+v) matrix differs from equivalent options:\n%v\n%v", pair[0], pair[1], test.cfg, m1, m2)
+			}
+			if !reflect.DeepEqual(m1.Operations(), m2.Operations()) {
+				t.Errorf("BuildConfig(%q, %q, %+v) operations differ from equivalent options", pair[0], pair[1], test.cfg)
+			}
+		}
+	}
+}
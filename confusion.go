@@ -0,0 +1,128 @@
+package levenshtein
+
+// confusion is a pair of strings which are commonly confused for each other,
+// such that swapping one for the other has a configured cost.
+type confusion struct {
+	from []rune
+	to   []rune
+}
+
+// ConfusionPairs is an option which allows you to provide a set of strings
+// which are commonly confused for one another - e.g. by OCR, which often
+// mistakes "rn" for "m", or "l" for "1" - along with a cost for swapping
+// one for the other. Each pair applies in both directions, and the strings
+// need not be the same length, so this allows low-cost substitutions of
+// several characters at once. Pairs in which either string is empty are
+// ignored. Confusions are reported by Operations as the swaps, removals and
+// insertions which transform one string into the other, with the cost of the
+// confusion attributed to the last of them. Filling the matrix requires
+// looking back more than one row, so comparisons against a compiled Pattern
+// allocate a full matrix. This option is ignored with SetAffineGap.
+func ConfusionPairs(pairs map[string]string, cost int) Option {
+	return func(m *Matrix) {
+		m.confCost = cost
+		m.confusions = m.confusions[:0:0]
+		for from, to := range pairs {
+			if from == "" || to == "" || from == to {
+				continue
+			}
+			m.confusions = append(m.confusions,
+				confusion{from: []rune(from), to: []rune(to)},
+				confusion{from: []rune(to), to: []rune(from)},
+			)
+		}
+		if len(m.confusions) == 0 {
+			m.confusions = nil
+		}
+	}
+}
+
+// matches reports whether the confusion can transform the first i characters
+// of the source string into the first j characters of the target string, by
+// replacing the characters at the end of each.
+func (c confusion) matches(source, target []rune, i, j int) bool {
+	if len(c.from) > i || len(c.to) > j {
+		return false
+	}
+	return equalRunes(source[i-len(c.from):i], c.from) && equalRunes(target[j-len(c.to):j], c.to)
+}
+
+// equalRunes reports whether the two slices hold the same runes.
+func equalRunes(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if a[k] != b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// confusionCell returns the cheapest cost of reaching cell (i, j) of the
+// matrix via a confusion, and false if no confusion applies.
+func (m *Matrix) confusionCell(i, j int) (int, bool) {
+	best, ok := 0, false
+	for _, c := range m.confusions {
+		if c.matches(m.source, m.target, i, j) {
+			cost := m.at(i-len(c.from), j-len(c.to)) + m.confCost
+			if !ok || cost < best {
+				best, ok = cost, true
+			}
+		}
+	}
+	return best, ok
+}
+
+// confusionStep returns the confusion which leads to cell (i, j) of the
+// matrix at its minimum cost, and false if there is none.
+func (m *Matrix) confusionStep(i, j int) (confusion, bool) {
+	for _, c := range m.confusions {
+		if c.matches(m.source, m.target, i, j) && m.at(i-len(c.from), j-len(c.to))+m.confCost == m.at(i, j) {
+			return c, true
+		}
+	}
+	return confusion{}, false
+}
+
+// confusionSteps returns the number of confusions which lead to cell (i, j) of
+// the matrix at its minimum cost.
+func (m *Matrix) confusionSteps(i, j int) int {
+	n := 0
+	for _, c := range m.confusions {
+		if c.matches(m.source, m.target, i, j) && m.at(i-len(c.from), j-len(c.to))+m.confCost == m.at(i, j) {
+			n++
+		}
+	}
+	return n
+}
+
+// appendConfusionReversed appends the operations which make up the given
+// confusion, leading to cell (i, j) of the matrix, in reverse order.
+func (m *Matrix) appendConfusionReversed(ops []Operation, c confusion, i, j int) []Operation {
+	startI, startJ := i-len(c.from), j-len(c.to)
+	start := len(ops)
+	for k := 0; k < len(c.from) || k < len(c.to); k++ {
+		op := Operation{CumulativeCost: m.at(startI, startJ)}
+		switch {
+		case k >= len(c.to):
+			op.Type, op.Char, op.Index = Remove, c.from[k], j
+		case k >= len(c.from):
+			op.Type, op.Char, op.Index = Insert, c.to[k], startJ+k
+		case c.from[k] == c.to[k]:
+			op.Type, op.Char, op.Index = Keep, c.to[k], startJ+k
+		default:
+			op.Type, op.Char, op.FromChar, op.Index = Swap, c.to[k], c.from[k], startJ+k
+			op.IsCaseOnly = caseOnly(op.FromChar, op.Char)
+		}
+		ops = append(ops, op)
+	}
+	ops[len(ops)-1].CumulativeCost = m.at(i, j)
+
+	// Reverse the operations in place
+	for k, l := start, len(ops)-1; k < l; k, l = k+1, l-1 {
+		ops[k], ops[l] = ops[l], ops[k]
+	}
+	return ops
+}
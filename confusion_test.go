@@ -0,0 +1,59 @@
+package levenshtein_test
+
+import (
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func TestConfusionPairs(t *testing.T) {
+	confusions := map[string]string{"rn": "m", "l": "1", "vv": "w", "cl": "d"}
+	options := []levenshtein.Option{levenshtein.ConfusionPairs(confusions, 1), levenshtein.SetSwapCost(2)}
+	tests := []struct {
+		source, target string
+		expected       int
+	}{
+		{"rnodern", "modern", 1},
+		{"modern", "rnodern", 1}, // Confusions apply in both directions
+		{"rnodem", "modern", 2},
+		{"he1lo", "hello", 1},
+		{"vvorld", "world", 1},
+		{"clog", "dog", 1},
+		{"rn", "", 2},
+		{"horse", "arose", 4},
+		{"", "", 0},
+	}
+	for _, test := range tests {
+		m := levenshtein.Build(test.source, test.target, options...)
+		if dist := m.Distance(); dist != test.expected {
+			t.Errorf("Distance(%q, %q) = %d, expected %d", test.source, test.target, dist, test.expected)
+		}
+
+		// The distance is the same without a full matrix
+		if dist := levenshtein.Compile(test.source, options...).Distance(test.target); dist != test.expected {
+			t.Errorf("Pattern.Distance(%q, %q) = %d, expected %d", test.source, test.target, dist, test.expected)
+		}
+
+		ops := m.Operations()
+		result, err := levenshtein.Apply(test.source, ops)
+		if err != nil || result != test.target {
+			t.Errorf("Apply(%q, Operations()) = %q, %v, expected %q", test.source, result, err, test.target)
+		}
+		if err := levenshtein.VerifyResults(ops); err != nil {
+			t.Errorf("VerifyResults(Operations(%q, %q)): %v", test.source, test.target, err)
+		}
+		if len(ops) > 0 && ops[len(ops)-1].CumulativeCost != test.expected {
+			t.Errorf("Operations(%q, %q) cumulative cost = %d, expected %d", test.source, test.target, ops[len(ops)-1].CumulativeCost, test.expected)
+		}
+	}
+}
+
+func TestConfusionPairsUniquePath(t *testing.T) {
+	ocr := levenshtein.ConfusionPairs(map[string]string{"rn": "m"}, 0)
+	if !levenshtein.Build("rnodern", "modern", ocr).UniquePath() {
+		t.Errorf("UniquePath() = false, expected true")
+	}
+	if levenshtein.Build("rn", "m", levenshtein.ConfusionPairs(map[string]string{"rn": "m"}, 2)).UniquePath() {
+		t.Errorf("UniquePath() = true, expected false when a confusion ties with a swap and a removal")
+	}
+}
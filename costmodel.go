@@ -0,0 +1,58 @@
+package levenshtein
+
+// CostModel determines the cost of each type of edit operation, for each
+// character. It can be used to implement arbitrary per-character costs.
+type CostModel interface {
+	// Insert returns the cost of inserting the character.
+	Insert(r rune) int
+
+	// Remove returns the cost of removing the character.
+	Remove(r rune) int
+
+	// Swap returns the cost of swapping one character for a different one.
+	Swap(from, to rune) int
+
+	// Match returns the cost of keeping a character which is the same in
+	// both strings. This is usually zero.
+	Match(r rune) int
+}
+
+// DefaultCostModel is a CostModel with flat costs, which do not depend on the
+// characters involved. Keeping a character is always free. It is the model
+// used when no other cost options are provided (with the default costs).
+type DefaultCostModel struct {
+	InsertCost int
+	RemoveCost int
+	SwapCost   int
+}
+
+// Insert implements the CostModel interface.
+func (c DefaultCostModel) Insert(r rune) int { return c.InsertCost }
+
+// Remove implements the CostModel interface.
+func (c DefaultCostModel) Remove(r rune) int { return c.RemoveCost }
+
+// Swap implements the CostModel interface.
+func (c DefaultCostModel) Swap(from, to rune) int { return c.SwapCost }
+
+// Match implements the CostModel interface.
+func (c DefaultCostModel) Match(r rune) int { return 0 }
+
+// UseCostModel is an option which allows you to provide a CostModel which
+// determines the costs of all edit operations. If this option is provided,
+// all other cost options (e.g. SetInsertCost, SetSwapCostFunc,
+// CombiningMarksCost, and WildcardRune) are ignored - except that a
+// DefaultCostModel is simply equivalent to setting its costs with
+// SetInsertCost, SetRemoveCost, and SetSwapCost.
+func UseCostModel(costs CostModel) Option {
+	return func(m *Matrix) {
+		if flat, ok := costs.(DefaultCostModel); ok {
+			m.insertCost = flat.InsertCost
+			m.removeCost = flat.RemoveCost
+			m.swapCost = flat.SwapCost
+			m.costs = nil
+			return
+		}
+		m.costs = costs
+	}
+}
@@ -0,0 +1,114 @@
+package levenshtein_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+// flatModel is a CostModel equivalent to DefaultCostModel, which is not
+// special-cased by UseCostModel.
+type flatModel struct {
+	levenshtein.DefaultCostModel
+}
+
+// vowelModel is a CostModel under which vowels are cheap to insert, remove,
+// or swap for each other, and keeping a character has a small cost.
+type vowelModel struct{}
+
+func isVowel(r rune) bool { return strings.ContainsRune("aeiou", r) }
+
+func (vowelModel) Insert(r rune) int {
+	if isVowel(r) {
+		return 1
+	}
+	return 3
+}
+
+func (vowelModel) Remove(r rune) int {
+	if isVowel(r) {
+		return 1
+	}
+	return 3
+}
+
+func (vowelModel) Swap(from, to rune) int {
+	if isVowel(from) && isVowel(to) {
+		return 1
+	}
+	return 3
+}
+
+func (vowelModel) Match(r rune) int { return 1 }
+
+func TestUseCostModel(t *testing.T) {
+	pairs := [][2]string{{"horse", "arose"}, {"kitten", "sitting"}, {"", "abc"}, {"abc", ""}}
+	for _, pair := range pairs {
+		flat := levenshtein.DefaultCostModel{InsertCost: 2, RemoveCost: 3, SwapCost: 4}
+		want := levenshtein.Build(pair[0], pair[1],
+			levenshtein.SetInsertCost(2),
+			levenshtein.SetRemoveCost(3),
+			levenshtein.SetSwapCost(4),
+		)
+		for _, model := range []levenshtein.CostModel{flat, flatModel{flat}} {
+			got := levenshtein.Build(pair[0], pair[1], levenshtein.UseCostModel(model))
+			if got.Distance() != want.Distance() {
+				t.Errorf("%q, %q: Distance() = %d, want %d", pair[0], pair[1], got.Distance(), want.Distance())
+			}
+			if !reflect.DeepEqual(got.Operations(), want.Operations()) {
+				t.Errorf("%q, %q: Operations() = %v, want %v", pair[0], pair[1], got.Operations(), want.Operations())
+			}
+		}
+	}
+}
+
+func TestUseCostModelCustom(t *testing.T) {
+	for _, test := range []struct {
+		source, target string
+		dist           int
+	}{
+		{"", "", 0},
+		{"abc", "abc", 3},
+		{"cat", "cut", 3},
+		{"cat", "cbt", 5},
+		{"cart", "cat", 6},
+		{"bt", "bat", 3},
+	} {
+		matrix := levenshtein.Build(test.source, test.target, levenshtein.UseCostModel(vowelModel{}))
+		if dist := matrix.Distance(); dist != test.dist {
+			t.Errorf("%q, %q: Distance() = %d, want %d", test.source, test.target, dist, test.dist)
+		}
+
+		ops := matrix.Operations()
+		result, err := levenshtein.Apply(test.source, ops)
+		if err != nil || result != test.target {
+			t.Errorf("%q, %q: Apply(Operations()) = %q, %v", test.source, test.target, result, err)
+		}
+		if len(ops) > 0 && ops[len(ops)-1].CumulativeCost != test.dist {
+			t.Errorf("%q, %q: final CumulativeCost = %d, want %d", test.source, test.target, ops[len(ops)-1].CumulativeCost, test.dist)
+		}
+	}
+}
+
+func TestUseCostModelOverridesOptions(t *testing.T) {
+	// The model determines all costs, even those set by other options
+	model := levenshtein.UseCostModel(vowelModel{})
+	want := levenshtein.Distance("Cat", "cat", model)
+	for _, option := range []levenshtein.Option{
+		levenshtein.CaseChangeCost(0),
+		levenshtein.SetSwapCost(10),
+		levenshtein.WildcardRune('C'),
+	} {
+		if dist := levenshtein.Distance("Cat", "cat", model, option); dist != want {
+			t.Errorf("Distance(\"Cat\", \"cat\") with another cost option = %d, want %d", dist, want)
+		}
+		if dist := levenshtein.Distance("Cat", "cat", option, model); dist != want {
+			t.Errorf("Distance(\"Cat\", \"cat\") with another cost option first = %d, want %d", dist, want)
+		}
+	}
+	if want != 5 {
+		t.Errorf("Distance(\"Cat\", \"cat\") = %d, want 5", want)
+	}
+}
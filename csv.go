@@ -0,0 +1,55 @@
+package levenshtein
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSV returns the cells of the matrix as comma-separated values, e.g. for
+// importing into a spreadsheet while debugging, with a header row holding the
+// characters of the target string and a header column holding the characters
+// of the source string, laid out as in String. See WriteCSV.
+func (m *Matrix) CSV() string {
+	var b strings.Builder
+	m.WriteCSV(&b, true) // Writing to a strings.Builder cannot fail
+	return b.String()
+}
+
+// WriteCSV writes the cells of the matrix to the writer as comma-separated
+// values, one line per row of the matrix, optionally preceded by a header row
+// holding the characters of the target string, with each line preceded by a
+// header column holding the characters of the source string. The first header
+// cells, which have no corresponding character, are left empty. Characters
+// which have a special meaning in CSV (e.g. commas and quotes) are quoted.
+func (m *Matrix) WriteCSV(w io.Writer, headers bool) error {
+	cw := csv.NewWriter(w)
+	if headers {
+		record := []string{"", ""}
+		for _, r := range m.target {
+			record = append(record, string(r))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	for i := 0; i <= len(m.source); i++ {
+		var record []string
+		if headers {
+			if i == 0 {
+				record = append(record, "")
+			} else {
+				record = append(record, string(m.source[i-1]))
+			}
+		}
+		for j := 0; j <= len(m.target); j++ {
+			record = append(record, strconv.Itoa(m.at(i, j)))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -12,8 +12,18 @@
 package levenshtein
 
 import (
+	"encoding/binary"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
 )
 
 // Default costs for inserting, removing, and swapping characters.
@@ -23,6 +33,23 @@ const (
 	DefaultSwapCost   = 1
 )
 
+// ResultMode determines which operations returned by Operations have their
+// Result field populated.
+type ResultMode int8
+
+const (
+	// ResultFull populates the Result field of every operation with the
+	// intermediate result of performing that operation. This is the default.
+	ResultFull ResultMode = iota
+
+	// ResultNone leaves the Result field of every operation empty.
+	ResultNone
+
+	// ResultFinalOnly populates the Result field of the last operation only
+	// (with the target string). All other Result fields are left empty.
+	ResultFinalOnly
+)
+
 // OpType represents a type of edit operation.
 type OpType int8
 
@@ -33,8 +60,30 @@ const (
 	Swap
 )
 
+// opTypeNames holds any custom names set by SetOpTypeNames.
+var opTypeNames atomic.Value // map[OpType]string
+
+// SetOpTypeNames overrides the names returned by OpType.String (and hence
+// used when formatting operations), e.g. to localize them. Operation types
+// which are missing from the map keep their default English names, and
+// passing nil restores all the defaults. It is safe to call concurrently with
+// formatting operations, though the change applies to the whole program.
+func SetOpTypeNames(names map[OpType]string) {
+	copied := make(map[OpType]string, len(names))
+	for o, name := range names {
+		copied[o] = name
+	}
+	opTypeNames.Store(copied)
+}
+
 // String returns the string representation of an operation type.
 func (o OpType) String() string {
+	if names, _ := opTypeNames.Load().(map[OpType]string); names != nil {
+		if name, ok := names[o]; ok {
+			return name
+		}
+	}
+
 	switch o {
 	case Insert:
 		return "insert"
@@ -52,13 +101,20 @@ func (o OpType) String() string {
 // Operation represents one of the operations performed on a source string
 // during the process of converting it into a target string. Contains
 // information about the type of operation, the character affected, the index
-// at which the operation occured, and the intermediate result of performing
-// this operation.
+// at which the operation occured, the intermediate result of performing this
+// operation, and the total cost of all operations up to and including this
+// one. For swaps, Char is the character swapped in, and FromChar is the
+// character it replaced. IsCaseOnly reports whether a swap only changed the
+// case of the character (e.g. from "H" to "h"), rather than substituting a
+// different one.
 type Operation struct {
-	Type   OpType
-	Char   rune
-	Index  int
-	Result string
+	Type           OpType
+	Char           rune
+	FromChar       rune
+	IsCaseOnly     bool
+	Index          int
+	Result         string
+	CumulativeCost int
 }
 
 // String returns the string representation of an operation.
@@ -66,32 +122,160 @@ func (o Operation) String() string {
 	return fmt.Sprintf("%6s %c at index %d: %s", o.Type, o.Char, o.Index, o.Result)
 }
 
+// Format implements the fmt.Formatter interface, allowing operations to be
+// formatted in different ways:
+//
+//	%v, %s  the string representation returned by String
+//	%q      the string representation, quoted
+//	%c      the character alone, e.g. "a"
+//	%+c     the character, index, and intermediate result, e.g. "a at index 0: aorse"
+//
+// Widths, precisions and flags apply to the whole of the formatted operation,
+// as they would to a string - e.g. %30v pads it to 30 characters. Any other
+// verb is reported as a formatting error, as fmt does for unsupported verbs.
+func (o Operation) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v', 's', 'q':
+		fmt.Fprintf(f, formatString(f, verb), o.String())
+	case 'c':
+		s := string(o.Char)
+		if f.Flag('+') {
+			s += fmt.Sprintf(" at index %d: %s", o.Index, o.Result)
+		}
+		fmt.Fprintf(f, formatString(f, 's'), s)
+	default:
+		fmt.Fprintf(f, "%%!%c(levenshtein.Operation=%s)", verb, o.String())
+	}
+}
+
+// formatString reconstructs the directive which is being formatted, with the
+// given verb, so that the width, precision and flags can be passed on to fmt.
+func formatString(f fmt.State, verb rune) string {
+	directive := []byte{'%'}
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			directive = append(directive, byte(flag))
+		}
+	}
+	if width, ok := f.Width(); ok {
+		directive = strconv.AppendInt(directive, int64(width), 10)
+	}
+	if precision, ok := f.Precision(); ok {
+		directive = append(directive, '.')
+		directive = strconv.AppendInt(directive, int64(precision), 10)
+	}
+	return string(append(directive, string(verb)...))
+}
+
 // Matrix contains a two-dimensional matrix used for calculating edit
 // distances between two strings, and for retrieving a minimal list of edit
 // operations for converting the source string into the target string.
 type Matrix struct {
-	matrix     [][]int
-	source     []rune
-	target     []rune
-	insertCost int
-	removeCost int
-	swapCost   int
+	matrix       [][]int
+	matrix32     [][]int32
+	matrix16     [][]int16
+	source       []rune
+	target       []rune
+	insertCost   int
+	removeCost   int
+	swapCost     int
+	swapFunc     func(from, to rune) int
+	markCost     int
+	markCostOK   bool
+	caseCost     int
+	caseCostOK   bool
+	wildcard     rune
+	wildcardOK   bool
+	costs        CostModel
+	confusions   []confusion
+	confCost     int
+	firstCost    int
+	weights      []float64
+	bonus        int
+	affine       bool
+	gapOpen      int
+	gapExtend    int
+	gapInsert    [][]int
+	gapRemove    [][]int
+	maxInserts   int
+	maxRemoves   int
+	maxSwaps     int
+	limitRemoves int
+	limitSwaps   int
+	limitLayers  []int
+	wordFreqs    map[string]float64
+	trace        bool
+	origins      [][]uint8
+	transforms   []func(string) string
+	resultMode   ResultMode
+	prefer       OpType
+	preferOK     bool
 }
 
 // String returns a string representation of the edit matrix, with proper
-// column spacing.
+// column spacing. The first line contains the characters of the target string,
+// and each subsequent line contains one row of the matrix, preceded by the
+// corresponding character of the source string (or a blank, for the first
+// row). Columns are separated by a single space, and are right-aligned to the
+// width of the largest value in the matrix. Trailing spaces are trimmed from
+// each line, and there is no trailing newline. For example, the string
+// representation of the matrix for "ab" and "b" is:
+//
+//	    b
+//	  0 1
+//	a 1 1
+//	b 2 1
 func (m *Matrix) String() string {
+	return m.render(nil)
+}
+
+// PathString returns a string representation of the matrix like String, but
+// with the cells on the path taken by the minimal list of edit operations
+// (from the top left cell to the bottom right one) surrounded by brackets, to
+// show how the alignment was found.
+func (m *Matrix) PathString() string {
+	path := map[[2]int]bool{{0, 0}: true}
+	i, j := 0, 0 // Positions in the source and target strings
+	m.walk(len(m.source), len(m.target), func(op Operation) bool {
+		switch op.Type {
+		case Insert:
+			j++
+		case Remove:
+			i++
+		default:
+			i, j = i+1, j+1
+		}
+		path[[2]int{i, j}] = true
+		return true
+	})
+	return m.render(path)
+}
+
+// render returns a string representation of the matrix. If path is not nil,
+// each column is widened to make room for brackets, which surround the cells
+// in the path.
+func (m *Matrix) render(path map[[2]int]bool) string {
 	// Figure out what the largest value in the matrix is, and hence
 	// what the width of our columns should be
-	var max int
-	for _, row := range m.matrix {
-		for _, val := range row {
-			if val > max {
+	var max, min int
+	for i := 0; i <= len(m.source); i++ {
+		for j := 0; j <= len(m.target); j++ {
+			if val := m.at(i, j); val > max {
 				max = val
+			} else if val < min {
+				min = val
 			}
 		}
 	}
-	fmtStr := fmt.Sprintf("%%%dv", max/10+1)
+	width := len(strconv.Itoa(max))
+	if w := len(strconv.Itoa(min)); w > width {
+		width = w // Negative values need room for the sign
+	}
+	fmtStr := fmt.Sprintf("%%%dv", width)
+	pathStr := fmtStr
+	if path != nil {
+		fmtStr, pathStr = " "+fmtStr+" ", "["+fmtStr+"]"
+	}
 
 	// First row contains characters of the target word
 	strs := []string{
@@ -104,9 +288,9 @@ func (m *Matrix) String() string {
 
 	// Assemble the string representation of the matrix
 	rowStrs := []string{
-		strings.Join(strs, " "),
+		strings.TrimRight(strings.Join(strs, " "), " "),
 	}
-	for i, row := range m.matrix {
+	for i := 0; i <= len(m.source); i++ {
 		// First column contains characters of the source word
 		var strs []string
 		if i == 0 {
@@ -116,10 +300,14 @@ func (m *Matrix) String() string {
 		}
 
 		// Fill in rest of the columns
-		for _, val := range row {
-			strs = append(strs, fmt.Sprintf(fmtStr, val))
+		for j := 0; j <= len(m.target); j++ {
+			if path[[2]int{i, j}] {
+				strs = append(strs, fmt.Sprintf(pathStr, m.at(i, j)))
+			} else {
+				strs = append(strs, fmt.Sprintf(fmtStr, m.at(i, j)))
+			}
 		}
-		rowStrs = append(rowStrs, strings.Join(strs, " "))
+		rowStrs = append(rowStrs, strings.TrimRight(strings.Join(strs, " "), " "))
 	}
 	return strings.Join(rowStrs, "\n")
 }
@@ -156,28 +344,327 @@ func SetSwapCost(cost int) Option {
 	}
 }
 
+// SetSwapCostFunc is an option which allows you to provide a function that
+// determines the cost of swapping one character for another, for cases where
+// some substitutions should be cheaper than others. The function is only
+// called for characters which differ - keeping a character is always free. If
+// this option is provided, any cost set by SetSwapCost is ignored.
+func SetSwapCostFunc(f func(from, to rune) int) Option {
+	return func(m *Matrix) {
+		m.swapFunc = f
+	}
+}
+
+// CombiningMarksCost is an option which allows you to set a custom cost for
+// inserting or removing combining marks (e.g. the combining acute accent,
+// U+0301), so that differences in diacritics count for less than differences
+// in base characters. Note that this only applies to decomposed text: a
+// precomposed character such as 'é' (U+00E9) is not a combining mark, so
+// inputs may need to be normalized to NFD first.
+func CombiningMarksCost(cost int) Option {
+	return func(m *Matrix) {
+		m.markCost = cost
+		m.markCostOK = true
+	}
+}
+
+// CaseChangeCost is an option which allows you to set a custom cost for
+// swapping characters which differ only in case (e.g. 'H' and 'h'), so that
+// case changes count as cheap but non-zero edits, rather than being ignored
+// entirely (see IgnoreCase). Such changes are reported as swaps by
+// Operations. Simple Unicode case folding is used to compare the characters.
+// Like the other cost options, it is ignored if UseCostModel is provided.
+func CaseChangeCost(cost int) Option {
+	return func(m *Matrix) {
+		m.caseCost = cost
+		m.caseCostOK = true
+	}
+}
+
+// equalFold reports whether the two characters are equal under simple Unicode
+// case folding.
+func equalFold(a, b rune) bool {
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return a == b
+}
+
+// caseOnly reports whether two different runes differ only by case.
+func caseOnly(a, b rune) bool {
+	return a != b && equalFold(a, b)
+}
+
+// WildcardRune is an option which allows you to designate a character as a
+// wildcard, which matches any other character. Swapping a wildcard in either
+// the source or the target string for any other character is free. Such
+// matches are reported as zero-cost swaps by Operations, so that applying the
+// operations still yields the target string.
+func WildcardRune(r rune) Option {
+	return func(m *Matrix) {
+		m.wildcard = r
+		m.wildcardOK = true
+	}
+}
+
+// FirstCharWeight is an option which multiplies the cost of any operation
+// involving the first character of either string - i.e. inserting the first
+// character of the target string, removing the first character of the source
+// string, or swapping either of them - by the given multiplier. This is useful
+// when matching names, in which the initial letter rarely changes, so that
+// candidates which preserve the first letter are ranked higher. With
+// SetAffineGap, only swaps are weighted.
+func FirstCharWeight(multiplier int) Option {
+	return func(m *Matrix) {
+		m.firstCost = multiplier
+	}
+}
+
+// PreferOperation is an option which determines which operation is chosen
+// when several achieve the minimum cost for a cell of the matrix (e.g. when
+// swapping a character costs the same as removing it and inserting another).
+// By default, insertions are preferred, then removals, then swaps. This does
+// not change the edit distance, but it does change which of several equally
+// good lists of operations is returned by Operations, and the order of the
+// operations returned by CellOrigin (with WithTrace). It is ignored with
+// SetAffineGap.
+func PreferOperation(op OpType) Option {
+	return func(m *Matrix) {
+		m.prefer = op
+		m.preferOK = true
+	}
+}
+
+// SetPositionWeights is an option which scales the cost of removing or
+// swapping each character of the source string by the weight at the same
+// position (in runes), so that edits to some regions of the source (e.g. the
+// stem of a word) count for more than others. Characters beyond the end of the
+// weights have a weight of 1. Since the edit matrix holds integers, each
+// weighted cost is rounded to the nearest integer (with halves rounded away
+// from zero), so the base costs may need to be scaled up for fractional
+// weights to have an effect - e.g. with the default costs, any weight below
+// 0.5 makes an edit free. Weights should not be negative.
+func SetPositionWeights(weights []float64) Option {
+	return func(m *Matrix) {
+		m.weights = append([]float64(nil), weights...)
+	}
+}
+
+// WordBoundaryBonus is an option which reduces the cost of keeping a character
+// by the given amount if it starts a word (i.e. if it is not whitespace, and
+// is either the first character or follows whitespace) in both strings. This
+// favors alignments which keep the words of multi-word phrases intact, rather
+// than matching up characters from different words. The amount should be
+// positive, and the bonus is not affected by SetPositionWeights or
+// FirstCharWeight. Since keeping a character can then have a negative cost,
+// distances are no longer necessarily zero for identical strings (or indeed
+// non-negative), and longer prefixes of the strings may be at a smaller
+// distance than shorter ones. To allow for this, comparisons against a
+// maximum distance (e.g. CappedDistance, or Pattern.Within) do not stop
+// early, and Symmetric is still reported as usual, but the distance should be
+// treated as a score for ranking alignments, rather than as a metric. Note
+// that functions which return -1 when there is no distance at all (e.g.
+// DistanceToSet, for an empty set) can then also return -1 as a real
+// distance.
+func WordBoundaryBonus(amount int) Option {
+	return func(m *Matrix) {
+		m.bonus = amount
+	}
+}
+
+// IgnoreFunc is an option which causes any characters for which the function
+// returns true to be removed from both strings before the edit matrix is
+// built - e.g. formatting characters such as spaces and dashes. The list of
+// edit operations reflects the stripped strings.
+func IgnoreFunc(ignore func(r rune) bool) Option {
+	return func(m *Matrix) {
+		m.transforms = append(m.transforms, func(s string) string {
+			return strings.Map(func(r rune) rune {
+				if ignore(r) {
+					return -1
+				}
+				return r
+			}, s)
+		})
+	}
+}
+
+// IgnoreRunes is an option which causes the given characters to be removed
+// from both strings before the edit matrix is built. It is equivalent to
+// IgnoreFunc with a function which returns true for the given characters.
+func IgnoreRunes(runes ...rune) Option {
+	return IgnoreFunc(func(r rune) bool {
+		for _, ignored := range runes {
+			if r == ignored {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// IgnoreSubstrings is an option which causes all occurrences of the given
+// substrings to be removed from both strings before the edit matrix is built
+// - e.g. boilerplate such as " (New)" or " Ltd.". The strings are scanned
+// once, from left to right, and where several substrings match at the same
+// position, the longest is removed, so overlapping removals are deterministic.
+// Any new occurrences created by a removal are kept. Empty substrings are
+// ignored.
+// The list of edit operations reflects the stripped strings.
+func IgnoreSubstrings(subs ...string) Option {
+	sorted := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		if sub != "" {
+			sorted = append(sorted, sub)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i]) != len(sorted[j]) {
+			return len(sorted[i]) > len(sorted[j])
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	// A Replacer prefers earlier arguments when several match at the same
+	// position, which makes it prefer longer substrings
+	pairs := make([]string, 0, 2*len(sorted))
+	for _, sub := range sorted {
+		pairs = append(pairs, sub, "")
+	}
+	replacer := strings.NewReplacer(pairs...)
+	return func(m *Matrix) {
+		m.transforms = append(m.transforms, replacer.Replace)
+	}
+}
+
+// IgnoreCase is an option which causes both strings to be case-folded before
+// the edit matrix is built, so that differences in case are ignored. Full
+// Unicode case folding is used, so e.g. "Straße" and "STRASSE" are
+// considered equal. The list of edit operations reflects the folded strings.
+func IgnoreCase() Option {
+	return func(m *Matrix) {
+		m.transforms = append(m.transforms, func(s string) string {
+			return cases.Fold().String(s)
+		})
+	}
+}
+
+// IgnoreCaseASCII is an option which causes the ASCII letters A-Z to be
+// converted to lowercase in both strings before the edit matrix is built. It
+// is cheaper than IgnoreCase, since it does not consult the Unicode tables,
+// but it leaves all non-ASCII characters untouched (e.g. 'É' and 'é' are still
+// considered different), so it should only be used for ASCII data.
+func IgnoreCaseASCII() Option {
+	return func(m *Matrix) {
+		m.transforms = append(m.transforms, lowerASCII)
+	}
+}
+
+// lowerASCII converts the ASCII letters A-Z in the string to lowercase.
+func lowerASCII(s string) string {
+	for i := 0; i < len(s); i++ {
+		if 'A' <= s[i] && s[i] <= 'Z' {
+			b := []byte(s)
+			for ; i < len(b); i++ {
+				if 'A' <= b[i] && b[i] <= 'Z' {
+					b[i] += 'a' - 'A'
+				}
+			}
+			return string(b)
+		}
+	}
+	return s
+}
+
+// SetResultMode is an option which allows you to control which operations have
+// their Result field populated. Building the intermediate results requires an
+// allocation per operation, which can be avoided when they are not needed. If
+// this option is not provided, ResultFull is used.
+func SetResultMode(mode ResultMode) Option {
+	return func(m *Matrix) {
+		m.resultMode = mode
+	}
+}
+
 // Builds and fills a matrix which can be used to calculate the edit distance
 // between the two strings, or to retrieve a list of edit operations required
 // to transform the source string into the target string.
 func Build(source, target string, options ...Option) *Matrix {
-	s := []rune(source)
-	t := []rune(target)
+	m := newConfig(options)
+	m.source = m.prepare(source)
+	m.target = m.prepare(target)
+	m.allocate()
+	m.fill()
+	return m
+}
+
+// InvalidUTF8Error is returned by BuildValid when one of the input strings is
+// not valid UTF-8.
+type InvalidUTF8Error struct {
+	Input  string // Which input was invalid: "source" or "target"
+	Offset int    // Byte offset of the first invalid sequence
+}
+
+// Error implements the error interface.
+func (e *InvalidUTF8Error) Error() string {
+	return fmt.Sprintf("levenshtein: invalid UTF-8 in %s at byte offset %d", e.Input, e.Offset)
+}
+
+// BuildValid is like Build, but returns an *InvalidUTF8Error if either string
+// is not valid UTF-8, rather than silently replacing invalid sequences with
+// the Unicode replacement character (U+FFFD), as Build does.
+func BuildValid(source, target string, options ...Option) (*Matrix, error) {
+	if offset := invalidUTF8(source); offset >= 0 {
+		return nil, &InvalidUTF8Error{Input: "source", Offset: offset}
+	}
+	if offset := invalidUTF8(target); offset >= 0 {
+		return nil, &InvalidUTF8Error{Input: "target", Offset: offset}
+	}
+	return Build(source, target, options...), nil
+}
+
+// invalidUTF8 returns the byte offset of the first invalid UTF-8 sequence in
+// the string, or -1 if it is valid.
+func invalidUTF8(s string) int {
+	for offset, r := range s {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(s[offset:]); size == 1 {
+				return offset
+			}
+		}
+	}
+	return -1
+}
+
+// newConfig returns an empty matrix holding the default costs, with the given
+// options applied.
+func newConfig(options []Option) *Matrix {
 	m := &Matrix{
-		matrix:     newMatrix(s, t),
-		source:     s,
-		target:     t,
 		insertCost: DefaultInsertCost,
 		removeCost: DefaultRemoveCost,
 		swapCost:   DefaultSwapCost,
+		firstCost:  1,
+		maxInserts: -1,
+		maxRemoves: -1,
+		maxSwaps:   -1,
 	}
 	for _, option := range options {
 		option(m)
 	}
-
-	m.fill()
 	return m
 }
 
+// prepare applies any configured transformations to an input string, and
+// converts it to runes.
+func (m *Matrix) prepare(s string) []rune {
+	for _, transform := range m.transforms {
+		s = transform(s)
+	}
+	return []rune(s)
+}
+
 func newMatrix(source, target []rune) [][]int {
 	m := make([][]int, len(source)+1)
 	for i := range m {
@@ -187,31 +674,191 @@ func newMatrix(source, target []rune) [][]int {
 }
 
 func (m *Matrix) fill() {
-	// Deletions to get to empty target string from input string
-	for i := 1; i <= len(m.source); i++ {
-		m.matrix[i][0] = i
+	switch {
+	case m.affine:
+		m.fillAffine()
+	case m.limited():
+		m.fillLimited()
+	default:
+		m.fillRows(-1)
+	}
+	if m.trace {
+		m.recordOrigins()
 	}
+}
 
-	// Insertions to get to target string from empty string
-	for j := 1; j <= len(m.target); j++ {
-		m.matrix[0][j] = j
+// fillFirstRow fills the first row of the matrix, which holds the insertions
+// needed to get to the target string from the empty string.
+func (m *Matrix) fillFirstRow(row []int) {
+	row[0] = 0
+	for j := 1; j < len(row); j++ {
+		row[j] = row[j-1] + m.insert(j)
 	}
+}
+
+// fillRow fills row i of the matrix, given the previous row. It returns the
+// smallest value in the row, which is a lower bound on the final distance.
+func (m *Matrix) fillRow(i int, prev, row []int) int {
+	// Deletions to get to empty target string from input string
+	row[0] = prev[0] + m.remove(i)
+	rowMin := row[0]
 
-	// Fill rest of matrix, using cheapest of three options for filling each
+	// Fill rest of row, using cheapest of three options for filling each
 	// cell (insert a character, delete a character, or swap a character)
-	for i := 1; i <= len(m.source); i++ {
-		for j := 1; j <= len(m.target); j++ {
-			swapCost := m.swapCost
-			if m.source[i-1] == m.target[j-1] {
-				swapCost = 0
+	for j := 1; j < len(row); j++ {
+		row[j] = min(
+			row[j-1]+m.insert(j),
+			prev[j]+m.remove(i),
+			prev[j-1]+m.swap(i, j),
+		)
+		if m.confusions != nil {
+			// Confusions look back more than one row, so this requires the
+			// whole matrix
+			if cost, ok := m.confusionCell(i, j); ok && cost < row[j] {
+				row[j] = cost
 			}
+		}
+		if row[j] < rowMin {
+			rowMin = row[j]
+		}
+	}
+	return rowMin
+}
+
+// insert returns the cost of inserting target character j-1.
+func (m *Matrix) insert(j int) int {
+	cost := m.insertCost
+	switch {
+	case m.costs != nil:
+		cost = m.costs.Insert(m.target[j-1])
+	case m.markCostOK && unicode.Is(unicode.M, m.target[j-1]):
+		cost = m.markCost
+	}
+	if j == 1 {
+		cost *= m.firstCost
+	}
+	return cost
+}
+
+// remove returns the cost of removing source character i-1.
+func (m *Matrix) remove(i int) int {
+	cost := m.removeCost
+	switch {
+	case m.costs != nil:
+		cost = m.costs.Remove(m.source[i-1])
+	case m.markCostOK && unicode.Is(unicode.M, m.source[i-1]):
+		cost = m.markCost
+	}
+	if i == 1 {
+		cost *= m.firstCost
+	}
+	return m.weigh(i, cost)
+}
 
-			m.matrix[i][j] = min(
-				m.matrix[i][j-1]+m.insertCost,
-				m.matrix[i-1][j]+m.removeCost,
-				m.matrix[i-1][j-1]+swapCost,
-			)
+// rowDistance calculates the edit distance between the matrix's source string
+// and the given target, using the given workspace (which must be large enough
+// to hold two rows of the matrix) rather than allocating a full matrix. It
+// gives up once the distance is known to be greater than max, unless max is
+// negative.
+func (m Matrix) rowDistance(target []rune, max int, rows []int) (int, bool) {
+	m.target = target
+	if m.affine || m.confusions != nil || m.limited() {
+		// Gotoh's algorithm and limits on the number of operations need extra
+		// layers, and confusions need more than two rows, so fill the whole
+		// matrix
+		m.allocate()
+		m.fill()
+		dist := m.Distance()
+		if m.infeasible() {
+			return dist, false // Not possible within the limits
+		}
+		return dist, max < 0 || dist <= max
+	}
+	prev, row := rows[:len(target)+1], rows[len(target)+1:2*len(target)+2]
+
+	m.fillFirstRow(prev)
+	for i := 1; i <= len(m.source); i++ {
+		rowMin := m.fillRow(i, prev, row)
+		if m.exceeds(rowMin, max) {
+			return rowMin, false
 		}
+		prev, row = row, prev
+	}
+
+	dist := prev[len(target)]
+	return dist, max < 0 || dist <= max
+}
+
+// improve calculates the edit distance between the source string and the
+// (prepared) target, reporting whether it is less than best - or, if no best
+// distance has been found yet, whether it is within any limits on the number
+// of operations. The calculation gives up as soon as it is clear that the
+// distance cannot be less than best.
+func (m Matrix) improve(target []rune, best int, found bool, rows []int) (int, bool) {
+	if !found {
+		return m.rowDistance(target, -1, rows)
+	}
+	// A negative max would never give up, so the distance must also be
+	// compared with best directly, in case best is not positive
+	dist, ok := m.rowDistance(target, best-1, rows)
+	return dist, ok && dist < best
+}
+
+// unbeatable reports whether no distance can be less than dist, so that a
+// search for the minimum distance can stop. Distances are never negative,
+// unless WordBoundaryBonus is used.
+func (m *Matrix) unbeatable(dist int) bool {
+	return dist == 0 && m.bonus == 0
+}
+
+// swap returns the cost of swapping source character i-1 for target character
+// j-1, or of keeping the character if the two are the same (which is zero,
+// unless a cost model says otherwise).
+func (m *Matrix) swap(i, j int) int {
+	cost := m.swapBase(i, j)
+	if i == 1 || j == 1 {
+		cost *= m.firstCost
+	}
+	cost = m.weigh(i, cost)
+	if m.bonus != 0 && m.source[i-1] == m.target[j-1] && wordStart(m.source, i) && wordStart(m.target, j) {
+		cost -= m.bonus
+	}
+	return cost
+}
+
+// wordStart reports whether character i-1 of the runes starts a word.
+func wordStart(runes []rune, i int) bool {
+	return !unicode.IsSpace(runes[i-1]) && (i == 1 || unicode.IsSpace(runes[i-2]))
+}
+
+// weigh scales the cost of an operation on source character i-1 by the
+// position weight for that character, if there is one.
+func (m *Matrix) weigh(i, cost int) int {
+	if i > len(m.weights) {
+		return cost
+	}
+	return int(math.Round(float64(cost) * m.weights[i-1]))
+}
+
+// swapBase returns the cost of swapping source character i-1 for target
+// character j-1, before any weighting is applied.
+func (m *Matrix) swapBase(i, j int) int {
+	from, to := m.source[i-1], m.target[j-1]
+	switch {
+	case m.costs != nil && from == to:
+		return m.costs.Match(from)
+	case m.costs != nil:
+		return m.costs.Swap(from, to)
+	case m.caseCostOK && caseOnly(from, to):
+		return m.caseCost
+	case from == to:
+		return 0
+	case m.wildcardOK && (from == m.wildcard || to == m.wildcard):
+		return 0
+	case m.swapFunc != nil:
+		return m.swapFunc(from, to)
+	default:
+		return m.swapCost
 	}
 }
 
@@ -224,6 +871,165 @@ func Distance(source, target string, options ...Option) int {
 	return Build(source, target, options...).Distance()
 }
 
+// DistanceWithinMatrix builds a matrix for the two strings row by row, giving
+// up once the distance is known to be greater than max (unless max is
+// negative), and returns the matrix along with the distance and whether it is
+// within max. This is useful for debugging why a pair of strings exceeded a
+// threshold. If the distance is within max, the matrix is complete. Otherwise,
+// the returned distance is the minimum of the last row which was filled,
+// which is a lower bound on the true distance: that row and the rows above it
+// are valid, but the cells of the rows below it are left as zero, so the
+// matrix's Distance and Operations methods should not be used. Matrices built
+// with SetAffineGap, ConfusionPairs, or limits on the number of operations
+// (see MaxInserts) are always filled completely, since a row whose minimum
+// exceeds max does not rule out the distance being within it.
+func DistanceWithinMatrix(source, target string, max int, options ...Option) (*Matrix, int, bool) {
+	m := newConfig(options)
+	m.source = m.prepare(source)
+	m.target = m.prepare(target)
+	m.allocate()
+	if m.affine || m.confusions != nil || m.limited() {
+		m.fill()
+		dist := m.Distance()
+		if m.infeasible() {
+			return m, dist, false // Not possible within the limits
+		}
+		return m, dist, max < 0 || dist <= max
+	}
+
+	if rowMin, ok := m.fillRows(max); !ok {
+		return m, rowMin, false
+	}
+	if m.trace {
+		m.recordOrigins()
+	}
+	dist := m.Distance()
+	return m, dist, max < 0 || dist <= max
+}
+
+// DistanceOrZero returns 0 immediately if the two strings are identical,
+// without converting them to runes or allocating an edit matrix, and
+// otherwise returns Distance(source, target, options...). This is faster for
+// workloads in which most strings are identical. Note that identical strings
+// are always at distance 0, even under a cost model with a non-zero cost for
+// keeping characters. The exception is WordBoundaryBonus, under which
+// identical strings can be at a negative distance, so they are compared as
+// usual.
+func DistanceOrZero(source, target string, options ...Option) int {
+	if source == target && (len(options) == 0 || newConfig(options).bonus == 0) {
+		return 0
+	}
+	return Distance(source, target, options...)
+}
+
+// CappedDistance returns the edit distance between the two strings, or cap if
+// the distance is greater than cap - i.e. min(Distance(a, b, options...),
+// cap). This saturating distance is useful for scoring systems in which all
+// sufficiently different strings score the same. It is also faster than
+// Distance for very different strings, since the calculation stops as soon as
+// it is clear that the distance is at least cap. If the target string cannot
+// be reached within the limits on the number of operations (see MaxInserts),
+// the strings are as different as can be, so it returns cap.
+func CappedDistance(a, b string, cap int, options ...Option) int {
+	m := newConfig(options)
+	m.source = m.prepare(a)
+	target := m.prepare(b)
+	if cap <= 0 {
+		// No early exit is possible, since max would be negative
+		dist, ok := m.rowDistance(target, -1, make([]int, 2*(len(target)+1)))
+		if !ok {
+			return cap // Not possible within the limits
+		}
+		return min(dist, cap)
+	}
+	if m.lengthCost(len(m.source), len(target)) >= cap {
+		return cap
+	}
+	dist, ok := m.rowDistance(target, cap-1, make([]int, 2*(len(target)+1)))
+	if !ok {
+		return cap
+	}
+	return dist
+}
+
+// DistanceFromOffset returns the edit distance between the two strings, given
+// that their first commonPrefix runes (after any transformations applied by
+// the options, such as IgnoreCase) are already known to be the same - e.g. in
+// incremental scenarios, where the shared prefix has already been found. Only
+// the remainders of the strings are compared, which gives the same distance,
+// since keeping a common prefix is always optimal. The prefix is validated, so
+// if the strings actually share fewer than commonPrefix runes, only the
+// shorter prefix they do share is skipped. Options under which keeping a
+// common prefix is not necessarily optimal (e.g. those which make costs depend
+// on position, such as SetPositionWeights and FirstCharWeight, or on the
+// characters inserted and removed, such as UseCostModel) disable the
+// optimization, and the whole of both strings is compared.
+func DistanceFromOffset(a, b string, commonPrefix int, options ...Option) int {
+	m := newConfig(options)
+	m.source = m.prepare(a)
+	target := m.prepare(b)
+	if !m.keepsCommonPrefix() {
+		commonPrefix = 0
+	}
+	k := 0
+	for k < commonPrefix && k < len(m.source) && k < len(target) && m.source[k] == target[k] {
+		k++
+	}
+	m.source = m.source[k:]
+	dist, _ := m.rowDistance(target[k:], -1, make([]int, 2*(len(target)-k+1)))
+	return dist
+}
+
+// keepsCommonPrefix reports whether a common prefix of the strings is always
+// kept by a minimal list of edit operations, so that it can be skipped when
+// calculating the distance. This requires the costs to be non-negative, and
+// independent of the positions and (for insertions and removals) the identity
+// of the characters.
+func (m *Matrix) keepsCommonPrefix() bool {
+	return !m.affine && !m.limited() && m.confusions == nil && m.costs == nil && m.swapFunc == nil &&
+		m.weights == nil && m.bonus == 0 && m.firstCost == 1 && !m.markCostOK && (!m.caseCostOK || m.caseCost >= 0) &&
+		m.insertCost >= 0 && m.removeCost >= 0 && m.swapCost >= 0
+}
+
+// DistanceLowerBound returns a lower bound on the edit distance between the
+// two strings under the default costs, which is cheap to calculate (in linear
+// time), so it can be used as a pre-filter to skip pairs which cannot be
+// within a threshold before calculating their distance. It is never an
+// overestimate: it is the bag distance of the strings (see BagDistance), which
+// is at least the difference in their lengths (in runes).
+func DistanceLowerBound(a, b string) int {
+	return BagDistance(a, b)
+}
+
+// BagDistance returns the bag distance between the two strings, i.e. the
+// number of characters of whichever string has more characters which are
+// missing from the other, treating each string as a multiset of characters
+// (so that their order is ignored). It is a lower bound on the edit distance
+// under the default costs, since each swap can correct at most one missing
+// character of each string, and each insertion or removal at most one.
+func BagDistance(a, b string) int {
+	counts := make(map[rune]int)
+	for _, r := range a {
+		counts[r]++
+	}
+	for _, r := range b {
+		counts[r]--
+	}
+
+	var surplusA, surplusB int
+	for _, count := range counts {
+		if count > 0 {
+			surplusA += count
+		} else {
+			surplusB -= count
+		}
+	}
+	if surplusA > surplusB {
+		return surplusA
+	}
+	return surplusB
+}
+
 // Operations builds a matrix and returns a minimal list of edit operations
 // required to transform the source string into the target string. This method
 // is a short-cut, useful in cases where you do not need to use the edit
@@ -233,65 +1039,450 @@ func Operations(source, target string, options ...Option) []Operation {
 	return Build(source, target, options...).Operations()
 }
 
+// At returns the value of the cell in row i and column j of the matrix - i.e.
+// the edit distance between the first i characters of the source string and
+// the first j characters of the target string. The matrix has
+// len(source)+1 rows and len(target)+1 columns (counted in runes). At panics
+// if i or j is out of range.
+func (m *Matrix) At(i, j int) int {
+	return m.at(i, j)
+}
+
+// PrefixDistance returns the minimum edit distance between the query string
+// and any prefix of the candidate string (including the empty prefix and the
+// whole candidate). This is useful for autocompletion, since it rewards
+// candidates which the query is a (possibly misspelled) prefix of, without
+// penalizing the remainder of the candidate.
+func PrefixDistance(query, candidate string, options ...Option) int {
+	m := newConfig(options)
+	m.source = m.prepare(query)
+	m.target = m.prepare(candidate)
+	return m.prefixDistance()
+}
+
+// SuffixDistance returns the minimum edit distance between the query string
+// and any suffix of the candidate string (including the empty suffix and the
+// whole candidate) - e.g. for matching file extensions. It is calculated by
+// reversing both strings and finding the prefix distance.
+func SuffixDistance(query, candidate string, options ...Option) int {
+	m := newConfig(options)
+	m.source = reverse(m.prepare(query))
+	m.target = reverse(m.prepare(candidate))
+	return m.prefixDistance()
+}
+
+// prefixDistance returns the minimum edit distance between the source string
+// and any prefix of the target string.
+func (m *Matrix) prefixDistance() int {
+	if m.affine || m.confusions != nil || m.limited() {
+		// Gotoh's algorithm and limits on the number of operations need extra
+		// layers, and confusions need more than two rows, so fill the whole
+		// matrix
+		m.allocate()
+		m.fill()
+		best, found := -1, false
+		for j := 0; j <= len(m.target); j++ {
+			if m.limited() && !m.reachable(len(m.source), j) {
+				continue // Not possible within the limits
+			}
+			val := m.at(len(m.source), j)
+			if !found || val < best {
+				best, found = val, true
+			}
+		}
+		return best
+	}
+
+	// The last row of the matrix holds the distances between the source and
+	// each prefix of the target, so only the smallest value is needed
+	prev, row := make([]int, len(m.target)+1), make([]int, len(m.target)+1)
+	m.fillFirstRow(prev)
+	rowMin := 0
+	for i := 1; i <= len(m.source); i++ {
+		rowMin = m.fillRow(i, prev, row)
+		prev, row = row, prev
+	}
+	return rowMin
+}
+
+// reverse reverses the runes in place, and returns them.
+func reverse(runes []rune) []rune {
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return runes
+}
+
+// FirstDifference returns the index (in runes) of the first position at which
+// the two strings differ, without building an edit matrix. If one string is a
+// prefix of the other, the index is the length of the shorter string. If the
+// strings are identical, equal is true, and the index is their length. This is
+// much cheaper than calculating the edit distance, so it can be used as a
+// pre-filter for strings which are likely to be identical.
+func FirstDifference(a, b string) (index int, equal bool) {
+	for len(a) > 0 && len(b) > 0 {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			return index, false
+		}
+		a, b = a[na:], b[nb:]
+		index++
+	}
+	return index, len(a) == 0 && len(b) == 0
+}
+
+// TruncateSource removes the last character of the source string (e.g. when
+// the user presses backspace), updating the matrix in place by discarding its
+// last row, so that it reflects the shortened source string without being
+// rebuilt. It returns the matrix, to allow calls to be chained. If the source
+// string is already empty, the matrix is unchanged.
+func (m *Matrix) TruncateSource() *Matrix {
+	if len(m.source) == 0 {
+		return m
+	}
+	m.source = m.source[:len(m.source)-1]
+	switch {
+	case m.matrix16 != nil:
+		m.matrix16 = m.matrix16[:len(m.matrix16)-1]
+	case m.matrix32 != nil:
+		m.matrix32 = m.matrix32[:len(m.matrix32)-1]
+	default:
+		m.matrix = m.matrix[:len(m.matrix)-1]
+	}
+	if m.affine {
+		m.gapInsert = m.gapInsert[:len(m.gapInsert)-1]
+		m.gapRemove = m.gapRemove[:len(m.gapRemove)-1]
+	}
+	if m.origins != nil {
+		m.origins = m.origins[:len(m.origins)-1]
+	}
+	return m
+}
+
 // Distance returns the edit distance between the two strings - i.e. the
 // minimum number of edits required to transform the source string into the
-// target string.
+// target string. The distance is only symmetric if the insertion and removal
+// costs are equal - see Symmetric.
 func (m *Matrix) Distance() int {
-	return m.matrix[len(m.source)][len(m.target)]
+	return m.at(len(m.source), len(m.target))
+}
+
+// Symmetric reports whether the costs used to build the matrix make the edit
+// distance symmetric - i.e. whether swapping the source and target strings
+// would yield the same distance. This is the case when the insertion and
+// removal costs are equal (as they are by default), as are any limits on the
+// number of insertions and removals, and there are no position weights (which
+// only apply to the source string). A custom swap cost function is assumed
+// to be symmetric itself, but a cost model (see UseCostModel) is not, since
+// it may charge different costs for inserting and removing a character.
+func (m *Matrix) Symmetric() bool {
+	return m.insertCost == m.removeCost && m.maxInserts == m.maxRemoves && m.weights == nil && m.costs == nil
 }
 
 // Operations returns a minimal list of edit operations required to transform
 // the source string into the target string.
 func (m *Matrix) Operations() []Operation {
-	ops := m.backtrace(len(m.source), len(m.target))
-	return ops[1:] // Remove dummy operation
+	return m.AppendOperations(make([]Operation, 0, len(m.source)+len(m.target)))
+}
+
+// OperationsTargetOrder returns the same minimal list of edit operations as
+// Operations, but ordered by position in the target string, for renderers
+// which follow the target rather than the source. Removals have no position in
+// the target string, so each run of removals is placed after any insertions
+// in the same gap between kept or swapped characters - i.e. immediately before
+// the next character of the source string which is kept or swapped (or at the
+// end). The Index, Result and CumulativeCost fields reflect the new order, so
+// the operations can still be applied in sequence.
+func (m *Matrix) OperationsTargetOrder() []Operation {
+	ops := m.backtrace(make([]Operation, 0, len(m.source)+len(m.target)), len(m.source), len(m.target))
+
+	// Record the cost of each operation, so that the cumulative costs can be
+	// recalculated after reordering
+	costs := make([]int, len(ops))
+	prev := 0
+	for k, op := range ops {
+		costs[k], prev = op.CumulativeCost-prev, op.CumulativeCost
+	}
+
+	ordered := make([]Operation, 0, len(ops))
+	orderedCosts := make([]int, 0, len(ops))
+	var removes []int // Indexes of the pending removals in ops
+	pos := 0          // Number of target characters produced so far
+	flush := func() {
+		for _, k := range removes {
+			op := ops[k]
+			op.Index = pos
+			ordered = append(ordered, op)
+			orderedCosts = append(orderedCosts, costs[k])
+		}
+		removes = removes[:0]
+	}
+	for k, op := range ops {
+		switch op.Type {
+		case Remove:
+			removes = append(removes, k)
+			continue
+		case Keep, Swap:
+			flush()
+		}
+		ordered = append(ordered, op)
+		orderedCosts = append(orderedCosts, costs[k])
+		pos++
+	}
+	flush()
+
+	cost := 0
+	for k := range ordered {
+		cost += orderedCosts[k]
+		ordered[k].CumulativeCost = cost
+	}
+	m.fillResults(ordered, true)
+	return ordered
+}
+
+// AppendOperations appends a minimal list of edit operations required to
+// transform the source string into the target string to dst, and returns the
+// extended slice. This allows a buffer to be reused when retrieving the
+// operations of many matrices, avoiding an allocation per matrix.
+func (m *Matrix) AppendOperations(dst []Operation) []Operation {
+	ops := m.backtrace(dst, len(m.source), len(m.target))
+	m.fillResults(ops[len(dst):], true)
+	return ops
+}
+
+// OperationsLimit returns at most the first k of the edit operations returned
+// by Operations, e.g. to preview the changes. The intermediate results of the
+// operations beyond the first k are not calculated, and those operations are
+// not returned, but since the path is traced back from the end of the matrix,
+// they may still be constructed along the way (e.g. for long strings, or
+// matrices built with SetAffineGap, ConfusionPairs, or limits on the number
+// of operations). If the result mode is ResultFinalOnly, the last operation
+// returned only carries the target string if it is the final operation.
+func (m *Matrix) OperationsLimit(k int) []Operation {
+	if k <= 0 {
+		return []Operation{}
+	}
+	ops := make([]Operation, 0, k)
+	complete := m.walk(len(m.source), len(m.target), func(op Operation) bool {
+		if len(ops) == k {
+			return false
+		}
+		ops = append(ops, op)
+		return true
+	})
+	m.fillResults(ops, complete)
+	return ops
+}
+
+// AlignmentHash returns a hash of the minimal list of edit operations - i.e.
+// of the sequence of operation types and characters, but not of their
+// intermediate results. Two matrices which produce the same operations have
+// the same hash. The hash is a 64-bit FNV-1a hash, and is stable across runs
+// and versions of this package, so it may be used as a cache key.
+func (m *Matrix) AlignmentHash() uint64 {
+	h := fnv.New64a()
+	var buf [5]byte
+	for _, op := range m.backtrace(nil, len(m.source), len(m.target)) {
+		buf[0] = byte(op.Type)
+		binary.LittleEndian.PutUint32(buf[1:], uint32(op.Char))
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
+
+// backtrace appends the operations which lead to cell (i, j) of the matrix to
+// ops, in the order in which they are performed.
+func (m *Matrix) backtrace(ops []Operation, i, j int) []Operation {
+	if !m.affine && !m.limited() && (i+j > RecursiveBacktraceLimit || m.confusions != nil) {
+		// Append the operations in reverse, and then reverse them in place,
+		// rather than buffering them as walkIterative does
+		start := len(ops)
+		ops = m.appendReversed(ops, i, j)
+		for k, l := start, len(ops)-1; k < l; k, l = k+1, l-1 {
+			ops[k], ops[l] = ops[l], ops[k]
+		}
+		return ops
+	}
+	m.walk(i, j, func(op Operation) bool {
+		ops = append(ops, op)
+		return true
+	})
+	return ops
 }
 
-func (m *Matrix) backtrace(i, j int) []Operation {
+// RecursiveBacktraceLimit is the length of the longest path through an edit
+// matrix (i.e. the combined length of the two strings, in runes) for which
+// the backtrace is performed recursively. Longer paths are traced
+// iteratively, which avoids deep recursion at the cost of buffering the
+// operations. Recursion is only faster for very short paths (see
+// BenchmarkBacktrace).
+var RecursiveBacktraceLimit = 32
+
+// walk calls visit for each of the operations which lead to cell (i, j) of
+// the matrix, in the order in which they are performed. If visit returns
+// false, the walk stops, and walk returns false.
+func (m *Matrix) walk(i, j int, visit func(op Operation) bool) bool {
 	switch {
-	case j > 0 && m.matrix[i][j-1]+m.insertCost == m.matrix[i][j]:
-		ops := m.backtrace(i, j-1)
-		prev := ops[len(ops)-1]
-		return append(ops, Operation{
-			Type:   Insert,
-			Char:   m.target[j-1],
-			Index:  j - 1,
-			Result: string(prev.Result[:j-1]) + string(m.target[j-1:j]) + string(prev.Result[j-1:]),
-		})
-	case i > 0 && m.matrix[i-1][j]+m.removeCost == m.matrix[i][j]:
-		ops := m.backtrace(i-1, j)
-		prev := ops[len(ops)-1]
-		return append(ops, Operation{
-			Type:   Remove,
-			Char:   m.source[i-1],
-			Index:  j,
-			Result: string(prev.Result[:j]) + string(prev.Result[j+1:]),
-		})
-	case i > 0 && j > 0 && m.matrix[i-1][j-1]+m.swapCost == m.matrix[i][j]:
-		ops := m.backtrace(i-1, j-1)
-		prev := ops[len(ops)-1]
-		return append(ops, Operation{
-			Type:   Swap,
-			Char:   m.target[j-1],
-			Index:  j - 1,
-			Result: string(prev.Result[:j-1]) + string(m.target[j-1:j]) + string(prev.Result[j:]),
-		})
-	case i > 0 && j > 0 && m.matrix[i-1][j-1] == m.matrix[i][j]:
-		ops := m.backtrace(i-1, j-1)
-		prev := ops[len(ops)-1]
-		return append(ops, Operation{
-			Type:   Keep,
-			Char:   m.target[j-1],
-			Index:  j - 1,
-			Result: prev.Result,
-		})
+	case m.affine:
+		return m.walkAffine(i, j, bestLayer, visit)
+	case m.limited():
+		return m.walkLimited(i, j, visit)
+	case i+j > RecursiveBacktraceLimit || m.confusions != nil:
+		return m.walkIterative(i, j, visit)
+	default:
+		return m.walkRecursive(i, j, visit)
+	}
+}
+
+// walkRecursive is the recursive implementation of walk.
+func (m *Matrix) walkRecursive(i, j int, visit func(op Operation) bool) bool {
+	op, prevI, prevJ, ok := m.step(i, j)
+	if !ok {
+		// Base case: we have reached the start of both strings
+		return true
+	}
+	return m.walkRecursive(prevI, prevJ, visit) && visit(op)
+}
+
+// walkIterative is the iterative implementation of walk. It collects the
+// operations in reverse, and then visits them in order.
+func (m *Matrix) walkIterative(i, j int, visit func(op Operation) bool) bool {
+	ops := m.appendReversed(make([]Operation, 0, i+j), i, j)
+	for k := len(ops) - 1; k >= 0; k-- {
+		if !visit(ops[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// appendReversed appends the operations which lead to cell (i, j) of the
+// matrix to ops, in reverse order.
+func (m *Matrix) appendReversed(ops []Operation, i, j int) []Operation {
+	for {
+		if op, prevI, prevJ, ok := m.step(i, j); ok {
+			ops = append(ops, op)
+			i, j = prevI, prevJ
+		} else if c, ok := m.confusionStep(i, j); ok {
+			ops = m.appendConfusionReversed(ops, c, i, j)
+			i, j = i-len(c.from), j-len(c.to)
+		} else {
+			return ops
+		}
+	}
+}
+
+// step returns the last of the operations which lead to cell (i, j) of the
+// matrix, along with the cell which precedes it. It returns false if (i, j) is
+// the first cell of the matrix. When several operations achieve the minimum,
+// the operation set by PreferOperation is preferred, and otherwise insertions
+// are preferred, then removals, then swaps or keeps.
+func (m *Matrix) step(i, j int) (op Operation, prevI, prevJ int, ok bool) {
+	if m.preferOK {
+		if op, prevI, prevJ, ok := m.stepType(m.prefer, i, j); ok {
+			return op, prevI, prevJ, true
+		}
+	}
+	for _, opType := range defaultOpOrder {
+		if op, prevI, prevJ, ok := m.stepType(opType, i, j); ok {
+			return op, prevI, prevJ, true
+		}
+	}
+	return Operation{}, i, j, false
+}
+
+// defaultOpOrder is the order in which operations are preferred when several
+// achieve the minimum cost for a cell, unless PreferOperation is used. Swaps
+// and keeps are mutually exclusive, so their relative order does not matter.
+var defaultOpOrder = [...]OpType{Insert, Remove, Swap, Keep}
+
+// opOrder returns the order in which operations are preferred when several
+// achieve the minimum cost for a cell.
+func (m *Matrix) opOrder() []OpType {
+	if !m.preferOK {
+		return defaultOpOrder[:]
+	}
+	order := []OpType{m.prefer}
+	for _, opType := range defaultOpOrder {
+		if opType != m.prefer {
+			order = append(order, opType)
+		}
+	}
+	return order
+}
+
+// stepType returns the operation of the given type which leads to cell (i, j)
+// of the matrix, along with the cell which precedes it, or false if an
+// operation of that type does not achieve the minimum cost for the cell.
+func (m *Matrix) stepType(opType OpType, i, j int) (op Operation, prevI, prevJ int, ok bool) {
+	switch {
+	case opType == Insert && j > 0 && m.at(i, j-1)+m.insert(j) == m.at(i, j):
+		return m.operation(Insert, i, j, m.at(i, j)), i, j - 1, true
+	case opType == Remove && i > 0 && m.at(i-1, j)+m.remove(i) == m.at(i, j):
+		return m.operation(Remove, i, j, m.at(i, j)), i - 1, j, true
+	case opType == Swap && i > 0 && j > 0 && m.source[i-1] != m.target[j-1] && m.at(i-1, j-1)+m.swap(i, j) == m.at(i, j):
+		return m.operation(Swap, i, j, m.at(i, j)), i - 1, j - 1, true
+	case opType == Keep && i > 0 && j > 0 && m.source[i-1] == m.target[j-1] && m.at(i-1, j-1)+m.swap(i, j) == m.at(i, j):
+		return m.operation(Keep, i, j, m.at(i, j)), i - 1, j - 1, true
+	default:
+		return Operation{}, i, j, false
+	}
+}
+
+// operation returns the operation of the given type which leads to cell
+// (i, j) of the matrix, with the given cumulative cost.
+func (m *Matrix) operation(opType OpType, i, j, cost int) Operation {
+	switch opType {
+	case Insert:
+		return Operation{Type: Insert, Char: m.target[j-1], Index: j - 1, CumulativeCost: cost}
+	case Remove:
+		return Operation{Type: Remove, Char: m.source[i-1], Index: j, CumulativeCost: cost}
+	case Swap:
+		return Operation{Type: Swap, Char: m.target[j-1], FromChar: m.source[i-1], IsCaseOnly: caseOnly(m.source[i-1], m.target[j-1]), Index: j - 1, CumulativeCost: cost}
 	default:
-		// Base case: return the original source string. This dummy operation
-		// is removed before the final list of operations is returned.
-		return []Operation{
-			{Result: string(m.source)},
+		return Operation{Type: Keep, Char: m.target[j-1], Index: j - 1, CumulativeCost: cost}
+	}
+}
+
+// fillResults populates the Result field of the given operations (which must
+// be the first operations, in order), according to the result mode. Complete
+// indicates whether the operations include the final operation.
+func (m *Matrix) fillResults(ops []Operation, complete bool) {
+	switch m.resultMode {
+	case ResultFull:
+		result := string(m.source)
+		runes := append([]rune(nil), m.source...)
+		for k := range ops {
+			if ops[k].Type != Keep {
+				runes, _ = applyOp(runes, ops[k])
+				result = string(runes)
+			}
+			ops[k].Result = result
 		}
+	case ResultFinalOnly:
+		if complete && len(ops) > 0 {
+			ops[len(ops)-1].Result = string(m.target)
+		}
+	}
+}
+
+// MinCost returns the minimum of the costs of reaching a cell of an edit
+// matrix by an insertion, a removal, or a swap (including a keep, whose cost
+// is passed as the cost of the swap), along with the type of operation which
+// achieved it - e.g. for building custom variants of the algorithm on top of
+// this package. Ties are broken in the same order as when reading off the
+// operations of a matrix: insertions are preferred to removals, which are
+// preferred to swaps.
+func MinCost(insert, remove, swap int) (cost int, which OpType) {
+	cost, which = insert, Insert
+	if remove < cost {
+		cost, which = remove, Remove
+	}
+	if swap < cost {
+		cost, which = swap, Swap
 	}
+	return cost, which
 }
 
 func min(nums ...int) int {
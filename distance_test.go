@@ -0,0 +1,1496 @@
+package levenshtein_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func FuzzBuild(f *testing.F) {
+	f.Add("horse", "arose", uint8(1), uint8(1), uint8(1))
+	f.Add("", "", uint8(1), uint8(1), uint8(1))
+	f.Add("", "abc", uint8(2), uint8(1), uint8(1))
+	f.Add("abc", "", uint8(1), uint8(2), uint8(1))
+	f.Add("héllo", "hëllø", uint8(1), uint8(1), uint8(0))
+	f.Add("日本語", "本日", uint8(0), uint8(3), uint8(5))
+	f.Fuzz(func(t *testing.T, source, target string, insertCost, removeCost, swapCost uint8) {
+		matrix := levenshtein.Build(source, target,
+			levenshtein.SetInsertCost(int(insertCost%8)),
+			levenshtein.SetRemoveCost(int(removeCost%8)),
+			levenshtein.SetSwapCost(int(swapCost%8)),
+		)
+		_ = matrix.String()
+		if dist := matrix.Distance(); dist < 0 {
+			t.Fatalf("negative distance %d", dist)
+		}
+
+		// Apply works on runes, so invalid UTF-8 is replaced by the time the
+		// operations are produced
+		ops := matrix.Operations()
+		result, err := levenshtein.Apply(source, ops)
+		if err != nil {
+			t.Fatalf("Apply(%q, ops): %v", source, err)
+		}
+		if want := string([]rune(target)); result != want {
+			t.Fatalf("Apply(%q, ops) = %q, want %q", source, result, want)
+		}
+		if len(ops) > 0 && ops[len(ops)-1].Result != result {
+			t.Fatalf("final Result = %q, want %q", ops[len(ops)-1].Result, result)
+		}
+		undone, err := levenshtein.Apply(result, levenshtein.Invert(ops))
+		if err != nil {
+			t.Fatalf("Apply(%q, Invert(ops)): %v", result, err)
+		}
+		if want := string([]rune(source)); undone != want {
+			t.Fatalf("Apply(%q, Invert(ops)) = %q, want %q", result, undone, want)
+		}
+		if len(ops) > 0 && ops[len(ops)-1].CumulativeCost != matrix.Distance() {
+			t.Fatalf("final CumulativeCost = %d, want %d", ops[len(ops)-1].CumulativeCost, matrix.Distance())
+		}
+	})
+}
+
+func TestBuildEmpty(t *testing.T) {
+	for _, test := range []struct {
+		source, target string
+		options        []levenshtein.Option
+		str            string
+		dist           int
+		ops            []levenshtein.Operation
+	}{
+		{
+			source: "", target: "",
+			str:  "\n  0",
+			dist: 0,
+			ops:  []levenshtein.Operation{},
+		},
+		{
+			source: "", target: "ab",
+			str:  "    a b\n  0 1 2",
+			dist: 2,
+			ops: []levenshtein.Operation{
+				{Type: levenshtein.Insert, Char: 'a', Index: 0, Result: "a", CumulativeCost: 1},
+				{Type: levenshtein.Insert, Char: 'b', Index: 1, Result: "ab", CumulativeCost: 2},
+			},
+		},
+		{
+			source: "ab", target: "",
+			str:  "\n  0\na 1\nb 2",
+			dist: 2,
+			ops: []levenshtein.Operation{
+				{Type: levenshtein.Remove, Char: 'a', Index: 0, Result: "b", CumulativeCost: 1},
+				{Type: levenshtein.Remove, Char: 'b', Index: 0, Result: "", CumulativeCost: 2},
+			},
+		},
+		{
+			source: "", target: "ab",
+			options: []levenshtein.Option{levenshtein.SetInsertCost(10)},
+			str:     "       a  b\n    0 10 20",
+			dist:    20,
+			ops: []levenshtein.Operation{
+				{Type: levenshtein.Insert, Char: 'a', Index: 0, Result: "a", CumulativeCost: 10},
+				{Type: levenshtein.Insert, Char: 'b', Index: 1, Result: "ab", CumulativeCost: 20},
+			},
+		},
+	} {
+		matrix := levenshtein.Build(test.source, test.target, test.options...)
+		if str := matrix.String(); str != test.str {
+			t.Errorf("Build(%q, %q).String() = %q, want %q", test.source, test.target, str, test.str)
+		}
+		if dist := matrix.Distance(); dist != test.dist {
+			t.Errorf("Build(%q, %q).Distance() = %d, want %d", test.source, test.target, dist, test.dist)
+		}
+		if at := matrix.At(0, 0); at != 0 {
+			t.Errorf("Build(%q, %q).At(0, 0) = %d, want 0", test.source, test.target, at)
+		}
+		if ops := matrix.Operations(); !reflect.DeepEqual(ops, test.ops) {
+			t.Errorf("Build(%q, %q).Operations() = %v, want %v", test.source, test.target, ops, test.ops)
+		}
+	}
+}
+
+var benchmarkPairs = [][2]string{
+	{"horse", "arose"},
+	{"kitten", "sitting"},
+	{"saturday", "sunday"},
+	{"levenshtein", "frankenstein"},
+}
+
+func BenchmarkOperations(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, pair := range benchmarkPairs {
+			matrix := levenshtein.Build(pair[0], pair[1], levenshtein.SetResultMode(levenshtein.ResultNone))
+			_ = matrix.Operations()
+		}
+	}
+}
+
+func BenchmarkAppendOperations(b *testing.B) {
+	b.ReportAllocs()
+	var buf []levenshtein.Operation
+	for i := 0; i < b.N; i++ {
+		for _, pair := range benchmarkPairs {
+			matrix := levenshtein.Build(pair[0], pair[1], levenshtein.SetResultMode(levenshtein.ResultNone))
+			buf = matrix.AppendOperations(buf[:0])
+		}
+	}
+}
+
+func TestAlignmentHash(t *testing.T) {
+	hash := levenshtein.Build("horse", "arose").AlignmentHash()
+	if want := uint64(0xae916e46da3865c2); hash != want {
+		t.Errorf("AlignmentHash() = %#x, want %#x", hash, want)
+	}
+
+	// Different source strings with the same alignment hash equal, since swaps
+	// only record the character swapped in
+	same := levenshtein.Build("morse", "arose", levenshtein.SetResultMode(levenshtein.ResultNone))
+	if same.AlignmentHash() != hash {
+		t.Errorf("AlignmentHash() differs for identical alignments")
+	}
+
+	for _, pair := range [][2]string{{"horse", "arise"}, {"horse", "rose"}, {"", ""}, {"horse", "horse"}} {
+		if levenshtein.Build(pair[0], pair[1]).AlignmentHash() == hash {
+			t.Errorf("AlignmentHash(%q, %q) equals hash of a different alignment", pair[0], pair[1])
+		}
+	}
+}
+
+func TestOperationFormat(t *testing.T) {
+	op := levenshtein.Operations("horse", "arose")[0]
+	str := "  swap a at index 0: aorse"
+	for _, test := range []struct {
+		format, expected string
+	}{
+		{"%v", str},
+		{"%+v", str},
+		{"%s", str},
+		{"%q", `"` + str + `"`},
+		{"%30v", "    " + str},
+		{"%-30s|", str + "    |"},
+		{"%.6s", "  swap"},
+		{"%c", "a"},
+		{"%+c", "a at index 0: aorse"},
+		{"%3c|", "  a|"},
+		{"%-3c|", "a  |"},
+		{"%d", "%!d(levenshtein.Operation=" + str + ")"},
+	} {
+		if got := fmt.Sprintf(test.format, op); got != test.expected {
+			t.Errorf("Sprintf(%q) = %q, want %q", test.format, got, test.expected)
+		}
+	}
+}
+
+func TestResultMode(t *testing.T) {
+	for _, test := range []struct {
+		source, target string
+		mode           levenshtein.ResultMode
+		results        []string
+	}{
+		{"horse", "arose", levenshtein.ResultFull, []string{"aorse", "arse", "arse", "arose", "arose", "arose"}},
+		{"horse", "arose", levenshtein.ResultNone, []string{"", "", "", "", "", ""}},
+		{"horse", "arose", levenshtein.ResultFinalOnly, []string{"", "", "", "", "", "arose"}},
+		{"abc", "", levenshtein.ResultFull, []string{"bc", "c", ""}},
+		{"abc", "", levenshtein.ResultNone, []string{"", "", ""}},
+		{"abc", "", levenshtein.ResultFinalOnly, []string{"", "", ""}},
+		{"", "ab", levenshtein.ResultFull, []string{"a", "ab"}},
+		{"", "ab", levenshtein.ResultNone, []string{"", ""}},
+		{"", "ab", levenshtein.ResultFinalOnly, []string{"", "ab"}},
+		{"", "", levenshtein.ResultFull, nil},
+		{"", "", levenshtein.ResultFinalOnly, nil},
+	} {
+		matrix := levenshtein.Build(test.source, test.target, levenshtein.SetResultMode(test.mode))
+		var results []string
+		for _, op := range matrix.Operations() {
+			results = append(results, op.Result)
+		}
+		if !reflect.DeepEqual(results, test.results) {
+			t.Errorf("%q, %q, mode %d: results = %q, want %q", test.source, test.target, test.mode, results, test.results)
+		}
+
+		// The distance does not depend on the result mode
+		if dist, want := matrix.Distance(), levenshtein.Build(test.source, test.target).Distance(); dist != want {
+			t.Errorf("%q, %q, mode %d: Distance() = %d, want %d", test.source, test.target, test.mode, dist, want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	for _, test := range []struct {
+		source, target string
+		max            int
+		options        []levenshtein.Option
+		within         bool
+		ratio          float64
+	}{
+		// Capped at max
+		{"kitten", "sitting", 3, nil, true, 1 - 3.0/7},
+		{"kitten", "sitting", 2, nil, false, 0},
+		{"kitten", "sitting", 0, nil, false, 0},
+		{"kitten", "kitten", 0, nil, true, 1},
+		{"", "", 0, nil, true, 1},
+		{"", "abc", 2, nil, false, 0},
+		{"", "abc", 3, nil, true, 0},
+		{"Kitten", "kitten", 0, []levenshtein.Option{levenshtein.IgnoreCase()}, true, 1},
+		// Uncapped
+		{"kitten", "sitting", -1, nil, true, 1 - 3.0/7},
+		{"horse", "arose", -1, nil, true, 1 - 3.0/5},
+		{"abc", "xyz", -1, nil, true, 0},
+		// Floored at 0 when the distance exceeds the longer length
+		{"ab", "cd", -1, []levenshtein.Option{levenshtein.SetSwapCost(5)}, true, 0},
+		{"ab", "cd", 3, []levenshtein.Option{levenshtein.SetSwapCost(5)}, false, 0},
+		// Capped at 1 when the distance is negative
+		{"ab cd", "ab cd", -1, []levenshtein.Option{levenshtein.WordBoundaryBonus(3)}, true, 1},
+		{"ab cd", "ab cx", -1, []levenshtein.Option{levenshtein.WordBoundaryBonus(3)}, true, 1},
+	} {
+		within, ratio := levenshtein.Score(test.source, test.target, test.max, test.options...)
+		if within != test.within || math.Abs(ratio-test.ratio) > 1e-9 {
+			t.Errorf("Score(%q, %q, %d) = %t, %v, want %t, %v", test.source, test.target, test.max, within, ratio, test.within, test.ratio)
+		}
+	}
+}
+
+func TestOperationsLimit(t *testing.T) {
+	for _, mode := range []levenshtein.ResultMode{levenshtein.ResultFull, levenshtein.ResultNone} {
+		matrix := levenshtein.Build("horse", "arose", levenshtein.SetResultMode(mode))
+		all := matrix.Operations()
+		for k := 0; k <= len(all)+1; k++ {
+			want := all
+			if k < len(all) {
+				want = all[:k]
+			}
+			if got := matrix.OperationsLimit(k); !reflect.DeepEqual(got, want) {
+				t.Errorf("OperationsLimit(%d) = %v, want %v", k, got, want)
+			}
+		}
+	}
+
+	matrix := levenshtein.Build("horse", "arose", levenshtein.SetResultMode(levenshtein.ResultFinalOnly))
+	if ops := matrix.OperationsLimit(3); ops[2].Result != "" {
+		t.Errorf("OperationsLimit(3)[2].Result = %q, want empty", ops[2].Result)
+	}
+	if ops := matrix.OperationsLimit(6); ops[5].Result != "arose" {
+		t.Errorf("OperationsLimit(6)[5].Result = %q, want %q", ops[5].Result, "arose")
+	}
+}
+
+func TestBuildValid(t *testing.T) {
+	for _, test := range []struct {
+		source, target string
+		err            *levenshtein.InvalidUTF8Error
+	}{
+		{"horse", "arose", nil},
+		{"h�rse", "arose", nil},
+		{"h\xffrse", "arose", &levenshtein.InvalidUTF8Error{Input: "source", Offset: 1}},
+		{"héllo", "wör\xc3", &levenshtein.InvalidUTF8Error{Input: "target", Offset: 4}},
+		{"\xed\xa0\x80", "", &levenshtein.InvalidUTF8Error{Input: "source", Offset: 0}},
+	} {
+		matrix, err := levenshtein.BuildValid(test.source, test.target)
+		if test.err == nil {
+			if err != nil {
+				t.Errorf("BuildValid(%q, %q): unexpected error: %v", test.source, test.target, err)
+			} else if want := levenshtein.Distance(test.source, test.target); matrix.Distance() != want {
+				t.Errorf("BuildValid(%q, %q).Distance() = %d, want %d", test.source, test.target, matrix.Distance(), want)
+			}
+			continue
+		}
+
+		var utf8Err *levenshtein.InvalidUTF8Error
+		if !errors.As(err, &utf8Err) || *utf8Err != *test.err {
+			t.Errorf("BuildValid(%q, %q) error = %v, want %v", test.source, test.target, err, test.err)
+		}
+	}
+}
+
+func BenchmarkIgnoreCase(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, pair := range benchmarkPairs {
+			levenshtein.Distance(pair[0], pair[1], levenshtein.IgnoreCase())
+		}
+	}
+}
+
+func BenchmarkIgnoreCaseASCII(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, pair := range benchmarkPairs {
+			levenshtein.Distance(pair[0], pair[1], levenshtein.IgnoreCaseASCII())
+		}
+	}
+}
+
+func TestDistanceOrZero(t *testing.T) {
+	for _, pair := range append(benchmarkPairs, [2]string{"", ""}, [2]string{"horse", "horse"}, [2]string{"Horse", "horse"}) {
+		for _, options := range [][]levenshtein.Option{nil, {levenshtein.SetSwapCost(5)}, {levenshtein.WordBoundaryBonus(3)}} {
+			want := levenshtein.Distance(pair[0], pair[1], options...)
+			if got := levenshtein.DistanceOrZero(pair[0], pair[1], options...); got != want {
+				t.Errorf("DistanceOrZero(%q, %q) = %d, want %d", pair[0], pair[1], got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkDistanceOrZeroIdentical(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, pair := range benchmarkPairs {
+			levenshtein.DistanceOrZero(pair[0], pair[0])
+		}
+	}
+}
+
+func BenchmarkDistanceOrZeroDifferent(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, pair := range benchmarkPairs {
+			levenshtein.DistanceOrZero(pair[0], pair[1])
+		}
+	}
+}
+
+func TestTruncateSource(t *testing.T) {
+	for _, options := range [][]levenshtein.Option{nil, {levenshtein.SetAffineGap(3, 1)}} {
+		source := []rune("hörses")
+		matrix := levenshtein.Build(string(source), "arose", options...)
+		for len(source) > 0 {
+			source = source[:len(source)-1]
+			matrix.TruncateSource()
+
+			want := levenshtein.Build(string(source), "arose", options...)
+			if matrix.Distance() != want.Distance() {
+				t.Errorf("%q: Distance() = %d, want %d", string(source), matrix.Distance(), want.Distance())
+			}
+			if !reflect.DeepEqual(matrix.Operations(), want.Operations()) {
+				t.Errorf("%q: Operations() = %v, want %v", string(source), matrix.Operations(), want.Operations())
+			}
+			if matrix.String() != want.String() {
+				t.Errorf("%q: String() = %q, want %q", string(source), matrix.String(), want.String())
+			}
+		}
+
+		// Truncating an empty source is a no-op
+		if dist := matrix.TruncateSource().Distance(); dist != levenshtein.Distance("", "arose", options...) {
+			t.Errorf("Distance() after truncating empty source = %d", dist)
+		}
+	}
+}
+
+func TestFirstCharWeight(t *testing.T) {
+	weight := levenshtein.FirstCharWeight(5)
+	for _, test := range []struct {
+		source, target string
+	}{
+		{"karen", "maren"},
+		{"karen", "aren"},
+		{"aren", "karen"},
+		{"karen", "karan"},
+		{"", "karen"},
+		{"karen", ""},
+		{"k", "m"},
+	} {
+		m := levenshtein.Build(test.source, test.target, weight)
+		ops := m.Operations()
+		if len(ops) == 0 {
+			t.Fatalf("Operations(%q, %q) returned no operations", test.source, test.target)
+		}
+		if cost := ops[len(ops)-1].CumulativeCost; cost != m.Distance() {
+			t.Errorf("Operations(%q, %q) cumulative cost = %d, expected %d", test.source, test.target, cost, m.Distance())
+		}
+		result, err := levenshtein.Apply(test.source, ops)
+		if err != nil || result != test.target {
+			t.Errorf("Apply(%q, ops) = %q, %v, expected %q", test.source, result, err, test.target)
+		}
+	}
+}
+
+func TestCellOrigin(t *testing.T) {
+	const (
+		I = levenshtein.Insert
+		R = levenshtein.Remove
+		S = levenshtein.Swap
+		K = levenshtein.Keep
+	)
+	expected := [][][]levenshtein.OpType{
+		{{}, {I}, {I}, {I}, {I}, {I}},
+		{{R}, {S}, {I, S}, {I, S}, {I, S}, {I, S}},
+		{{R}, {R, S}, {S}, {K}, {I}, {I}},
+		{{R}, {R, S}, {K}, {I, R, S}, {S}, {I, S}},
+		{{R}, {R, S}, {R}, {S}, {K}, {I, S}},
+		{{R}, {R, S}, {R}, {R, S}, {R, S}, {K}},
+	}
+
+	m := levenshtein.Build("horse", "arose", levenshtein.WithTrace())
+	for i, row := range expected {
+		for j, origin := range row {
+			if actual := m.CellOrigin(i, j); !reflect.DeepEqual(actual, origin) {
+				t.Errorf("CellOrigin(%d, %d) = %v, expected %v", i, j, actual, origin)
+			}
+		}
+	}
+
+	if origin := levenshtein.Build("horse", "arose").CellOrigin(1, 1); origin != nil {
+		t.Errorf("CellOrigin without WithTrace = %v, expected nil", origin)
+	}
+}
+
+func TestSimilarityPercent(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		options  []levenshtein.Option
+		expected int
+	}{
+		{"", "", nil, 100},
+		{"abc", "abc", nil, 100},
+		{"abc", "xyz", nil, 0},
+		{"", "abc", nil, 0},
+		{"abcd", "abcx", nil, 75},
+		{"abcde", "abcdx", nil, 80},
+		{"abcdefgh", "abcdefgx", nil, 88}, // 87.5 rounds up
+		{"abcdefgh", "abcdefxx", nil, 75}, // Exact
+		{"abcdefgh", "abcdexxx", nil, 63}, // 62.5 rounds up
+		{"abcdef", "abcdex", nil, 83},     // 83.33 rounds down
+		{"abc", "abx", nil, 67},           // 66.67 rounds up
+		{"abc", "xyz", []levenshtein.Option{levenshtein.SetSwapCost(5)}, 0},
+		{"ABC", "abc", []levenshtein.Option{levenshtein.IgnoreCase()}, 100},
+		// Negative distances are capped at 100%
+		{"ab cd", "ab cd", []levenshtein.Option{levenshtein.WordBoundaryBonus(3)}, 100},
+	}
+	for _, test := range tests {
+		if percent := levenshtein.SimilarityPercent(test.a, test.b, test.options...); percent != test.expected {
+			t.Errorf("SimilarityPercent(%q, %q) = %d, expected %d", test.a, test.b, percent, test.expected)
+		}
+	}
+}
+
+func TestRatioBatch(t *testing.T) {
+	pairs := [][2]string{
+		{"horse", "arose"},
+		{"", ""},
+		{"abc", "xyz"},
+		{"kitten", "sitting"},
+		{"ABC", "abc"},
+	}
+	for i := 0; i < 200; i++ {
+		pairs = append(pairs, [2]string{strings.Repeat("ab", i%7), strings.Repeat("ba", i%5)})
+	}
+
+	for _, options := range [][]levenshtein.Option{nil, {levenshtein.IgnoreCase()}} {
+		ratios := levenshtein.RatioBatch(pairs, options...)
+		if len(ratios) != len(pairs) {
+			t.Fatalf("RatioBatch returned %d ratios, expected %d", len(ratios), len(pairs))
+		}
+		for i, pair := range pairs {
+			if expected := levenshtein.SimilarityRatio(pair[0], pair[1], options...); ratios[i] != expected {
+				t.Errorf("RatioBatch()[%d] (%q, %q) = %v, expected %v", i, pair[0], pair[1], ratios[i], expected)
+			}
+		}
+	}
+
+	if ratios := levenshtein.RatioBatch(nil); len(ratios) != 0 {
+		t.Errorf("RatioBatch(nil) = %v, expected none", ratios)
+	}
+	if ratio := levenshtein.SimilarityRatio("horse", "arose"); ratio != 0.4 {
+		t.Errorf("SimilarityRatio(\"horse\", \"arose\") = %v, expected 0.4", ratio)
+	}
+}
+
+func TestOperationsTargetOrder(t *testing.T) {
+	for _, pair := range benchmarkPairs {
+		m := levenshtein.Build(pair[0], pair[1], levenshtein.SetSwapCost(2))
+		ops := m.OperationsTargetOrder()
+		if len(ops) != len(m.Operations()) {
+			t.Errorf("OperationsTargetOrder(%q, %q) returned %d operations, expected %d", pair[0], pair[1], len(ops), len(m.Operations()))
+		}
+		result, err := levenshtein.Apply(pair[0], ops)
+		if err != nil || result != pair[1] {
+			t.Errorf("Apply(%q, OperationsTargetOrder()) = %q, %v, expected %q", pair[0], result, err, pair[1])
+		}
+		if len(ops) > 0 && ops[len(ops)-1].CumulativeCost != m.Distance() {
+			t.Errorf("OperationsTargetOrder(%q, %q) cumulative cost = %d, expected %d", pair[0], pair[1], ops[len(ops)-1].CumulativeCost, m.Distance())
+		}
+		index := 0
+		for _, op := range ops {
+			if op.Index < index {
+				t.Errorf("OperationsTargetOrder(%q, %q) is not in target order: %v", pair[0], pair[1], ops)
+				break
+			}
+			index = op.Index
+		}
+	}
+}
+
+func TestCaseChangeCost(t *testing.T) {
+	options := []levenshtein.Option{levenshtein.SetSwapCost(3), levenshtein.CaseChangeCost(1)}
+	tests := []struct {
+		source, target string
+		expected       int
+	}{
+		{"horse", "horse", 0},
+		{"Horse", "horse", 1},
+		{"HORSE", "horse", 5},
+		{"Horse", "Morse", 2}, // Cheaper to remove and insert than to swap
+		{"ÉCOLE", "école", 5},
+		{"Straße", "STRASSE", 7},
+		{"ǅ", "ǆ", 1}, // Title case and lower case of the same letter
+	}
+	for _, test := range tests {
+		if dist := levenshtein.Distance(test.source, test.target, options...); dist != test.expected {
+			t.Errorf("Distance(%q, %q) = %d, expected %d", test.source, test.target, dist, test.expected)
+		}
+	}
+}
+
+func TestUniquePath(t *testing.T) {
+	tests := []struct {
+		source, target string
+		options        []levenshtein.Option
+		expected       bool
+	}{
+		{"", "", nil, true},
+		{"abc", "abc", nil, true},
+		{"", "abc", nil, true},
+		{"abc", "", nil, true},
+		{"kitten", "sitten", nil, true},
+		{"ab", "ba", nil, false},  // Swap both, or remove and insert
+		{"aa", "a", nil, false},   // Remove either a
+		{"abc", "xyz", nil, true}, // Three swaps is the cheapest
+		{"a", "b", []levenshtein.Option{levenshtein.SetSwapCost(2)}, false}, // Swap, or remove and insert
+		{"a", "b", []levenshtein.Option{levenshtein.SetSwapCost(3)}, false}, // Remove then insert, or insert then remove
+	}
+	for _, test := range tests {
+		if unique := levenshtein.Build(test.source, test.target, test.options...).UniquePath(); unique != test.expected {
+			t.Errorf("UniquePath(%q, %q) = %t, expected %t", test.source, test.target, unique, test.expected)
+		}
+	}
+}
+
+func TestDistanceWithinMatrix(t *testing.T) {
+	for _, pair := range benchmarkPairs {
+		full := levenshtein.Build(pair[0], pair[1])
+		for max := -1; max <= full.Distance()+1; max++ {
+			m, dist, ok := levenshtein.DistanceWithinMatrix(pair[0], pair[1], max)
+			if expected := max < 0 || full.Distance() <= max; ok != expected {
+				t.Errorf("DistanceWithinMatrix(%q, %q, %d) within = %t, expected %t", pair[0], pair[1], max, ok, expected)
+			}
+			if ok {
+				if dist != full.Distance() || m.String() != full.String() {
+					t.Errorf("DistanceWithinMatrix(%q, %q, %d) = %d, expected complete matrix with distance %d", pair[0], pair[1], max, dist, full.Distance())
+				}
+				continue
+			}
+
+			// Rows are valid up to the first whose minimum exceeds max
+			if dist <= max || dist > full.Distance() {
+				t.Errorf("DistanceWithinMatrix(%q, %q, %d) = %d, expected a lower bound greater than max", pair[0], pair[1], max, dist)
+			}
+			source, target := []rune(pair[0]), []rune(pair[1])
+			for i := 0; i <= len(source); i++ {
+				rowMin := full.At(i, 0)
+				for j := 0; j <= len(target); j++ {
+					if m.At(i, j) != full.At(i, j) {
+						t.Errorf("DistanceWithinMatrix(%q, %q, %d) cell (%d, %d) = %d, expected %d", pair[0], pair[1], max, i, j, m.At(i, j), full.At(i, j))
+					}
+					if full.At(i, j) < rowMin {
+						rowMin = full.At(i, j)
+					}
+				}
+				if rowMin > max {
+					for i++; i <= len(source); i++ {
+						for j := 0; j <= len(target); j++ {
+							if m.At(i, j) != 0 {
+								t.Errorf("DistanceWithinMatrix(%q, %q, %d) cell (%d, %d) = %d, expected unfilled", pair[0], pair[1], max, i, j, m.At(i, j))
+							}
+						}
+					}
+					break
+				}
+			}
+		}
+	}
+
+	// A confusion can jump over a row whose minimum exceeds max, so the
+	// matrix is filled completely
+	confusions := levenshtein.ConfusionPairs(map[string]string{"ab": "c"}, 0)
+	m, dist, ok := levenshtein.DistanceWithinMatrix("éa abAc", " cAc", 2, confusions)
+	if full := levenshtein.Build("éa abAc", " cAc", confusions); dist != 2 || !ok || m.String() != full.String() {
+		t.Errorf("DistanceWithinMatrix with ConfusionPairs = %d, %t, expected complete matrix with distance 2", dist, ok)
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	symmetric := [][]levenshtein.Option{
+		nil,
+		{levenshtein.SetSwapCost(2)},
+		{levenshtein.SetInsertCost(3), levenshtein.SetRemoveCost(3)},
+		{levenshtein.SetAffineGap(3, 1)},
+	}
+	asymmetric := [][]levenshtein.Option{
+		{levenshtein.SetInsertCost(2)},
+		{levenshtein.SetRemoveCost(2)},
+		{levenshtein.UseCostModel(vowelModel{})},
+		// Position weights only apply to the source string
+		{levenshtein.SetPositionWeights([]float64{1, 5})},
+	}
+
+	keys := map[string]int{}
+	for k, options := range append(symmetric, asymmetric...) {
+		ab := levenshtein.CacheKey("horse", "arose", options...)
+		ba := levenshtein.CacheKey("arose", "horse", options...)
+		if k < len(symmetric) && ab != ba {
+			t.Errorf("CacheKey with symmetric options %d depends on argument order: %q, %q", k, ab, ba)
+		}
+		if k >= len(symmetric) && ab == ba {
+			t.Errorf("CacheKey with asymmetric options %d does not depend on argument order: %q", k, ab)
+		}
+		if prev, ok := keys[ab]; ok {
+			t.Errorf("CacheKey with options %d collides with options %d: %q", k, prev, ab)
+		}
+		keys[ab] = k
+	}
+
+	// Keys must not be ambiguous when the strings contain separators
+	if levenshtein.CacheKey(`a" "b`, "c") == levenshtein.CacheKey("a", `b" "c`) {
+		t.Errorf("CacheKey is ambiguous for strings containing quotes and spaces")
+	}
+}
+
+// withBacktraceLimit runs f with RecursiveBacktraceLimit set to limit.
+func withBacktraceLimit(limit int, f func()) {
+	defer func(prev int) { levenshtein.RecursiveBacktraceLimit = prev }(levenshtein.RecursiveBacktraceLimit)
+	levenshtein.RecursiveBacktraceLimit = limit
+	f()
+}
+
+func TestBacktraceIterative(t *testing.T) {
+	pairs := append([][2]string{
+		{"", ""},
+		{"", "abc"},
+		{strings.Repeat("abcde", 100), strings.Repeat("badce", 90)},
+	}, benchmarkPairs...)
+	for _, pair := range pairs {
+		m := levenshtein.Build(pair[0], pair[1], levenshtein.SetSwapCost(2))
+		var recursive, iterative []levenshtein.Operation
+		var recursiveCodes, iterativeCodes []levenshtein.OpCode
+		withBacktraceLimit(math.MaxInt, func() { recursive, recursiveCodes = m.Operations(), m.OpCodes() })
+		withBacktraceLimit(0, func() { iterative, iterativeCodes = m.Operations(), m.OpCodes() })
+		if !reflect.DeepEqual(recursive, iterative) {
+			t.Errorf("Iterative Operations(%q, %q) = %v, expected %v", pair[0], pair[1], iterative, recursive)
+		}
+		if err := levenshtein.VerifyResults(iterative); err != nil {
+			t.Errorf("VerifyResults(Operations(%q, %q)): %v", pair[0], pair[1], err)
+		}
+		if !reflect.DeepEqual(recursiveCodes, iterativeCodes) {
+			t.Errorf("Iterative OpCodes(%q, %q) = %v, expected %v", pair[0], pair[1], iterativeCodes, recursiveCodes)
+		}
+	}
+}
+
+func BenchmarkBacktrace(b *testing.B) {
+	for _, size := range []int{16, 64, 256, 1024, 4096} {
+		m := levenshtein.Build(strings.Repeat("abcd", size/8), strings.Repeat("bcda", size/8), levenshtein.SetResultMode(levenshtein.ResultNone))
+		for _, test := range []struct {
+			name  string
+			limit int
+		}{
+			{"recursive", math.MaxInt},
+			{"iterative", 0},
+		} {
+			b.Run(fmt.Sprintf("%s/%d", test.name, size), func(b *testing.B) {
+				withBacktraceLimit(test.limit, func() {
+					for i := 0; i < b.N; i++ {
+						m.Operations()
+					}
+				})
+			})
+		}
+	}
+}
+
+func TestSetPositionWeights(t *testing.T) {
+	stem := levenshtein.SetPositionWeights([]float64{5, 5, 5, 5})
+	tests := []struct {
+		source, target string
+		options        []levenshtein.Option
+		expected       int
+	}{
+		{"walking", "talking", nil, 1},
+		{"walking", "talking", []levenshtein.Option{stem}, 5},
+		{"walking", "walked", []levenshtein.Option{stem}, 3}, // The suffix is unweighted
+		{"ab", "b", []levenshtein.Option{levenshtein.SetPositionWeights([]float64{0.4})}, 0},
+		{"ab", "b", []levenshtein.Option{levenshtein.SetPositionWeights([]float64{0.5})}, 1},
+		{"ab", "b", []levenshtein.Option{levenshtein.SetPositionWeights([]float64{2.5})}, 3},
+	}
+	for _, test := range tests {
+		if dist := levenshtein.Distance(test.source, test.target, test.options...); dist != test.expected {
+			t.Errorf("Distance(%q, %q) = %d, expected %d", test.source, test.target, dist, test.expected)
+		}
+	}
+
+	// When edits to the stem and the suffix cost the same unweighted, a high
+	// weight on the stem causes the suffix to be edited instead
+	for _, op := range levenshtein.Operations("abab", "ab", stem) {
+		if op.Type == levenshtein.Remove && op.Index < 2 {
+			t.Errorf("Operations(\"abab\", \"ab\") edits the stem: %v", op)
+		}
+	}
+}
+
+func TestNetLengthChange(t *testing.T) {
+	pairs := append([][2]string{{"", "abc"}, {"abc", ""}, {"héllo", "hello wörld"}}, benchmarkPairs...)
+	for _, pair := range pairs {
+		for _, options := range [][]levenshtein.Option{
+			nil,
+			{levenshtein.SetSwapCost(5)},
+			{levenshtein.SetAffineGap(3, 1)},
+			{levenshtein.ConfusionPairs(map[string]string{"ur": "n"}, 0)},
+		} {
+			m := levenshtein.Build(pair[0], pair[1], options...)
+			expected := len([]rune(pair[1])) - len([]rune(pair[0]))
+			if change := m.NetLengthChange(); change != expected {
+				t.Errorf("NetLengthChange(%q, %q) = %d, expected %d", pair[0], pair[1], change, expected)
+			}
+		}
+	}
+}
+
+func TestPreferOperation(t *testing.T) {
+	const (
+		I = levenshtein.Insert
+		R = levenshtein.Remove
+		S = levenshtein.Swap
+	)
+	tests := []struct {
+		prefer   []levenshtein.Option
+		expected []levenshtein.OpType
+	}{
+		{nil, []levenshtein.OpType{I, R, S}},
+		{[]levenshtein.Option{levenshtein.PreferOperation(I)}, []levenshtein.OpType{I, R, S}},
+		{[]levenshtein.Option{levenshtein.PreferOperation(R)}, []levenshtein.OpType{R, I, S}},
+		{[]levenshtein.Option{levenshtein.PreferOperation(S)}, []levenshtein.OpType{S, I, R}},
+	}
+	for _, test := range tests {
+		// Cell (3, 3) of the "horse"/"arose" matrix can be reached by any
+		// operation at the same cost
+		options := append([]levenshtein.Option{levenshtein.WithTrace()}, test.prefer...)
+		m := levenshtein.Build("horse", "arose", options...)
+		if origin := m.CellOrigin(3, 3); !reflect.DeepEqual(origin, test.expected) {
+			t.Errorf("CellOrigin(3, 3) = %v, expected %v", origin, test.expected)
+		}
+
+		// The preference does not change the distance, and the operations
+		// are still valid
+		if dist := m.Distance(); dist != 3 {
+			t.Errorf("Distance() = %d, expected 3", dist)
+		}
+		ops := m.Operations()
+		if result, err := levenshtein.Apply("horse", ops); err != nil || result != "arose" {
+			t.Errorf("Apply(Operations()) = %q, %v, expected \"arose\"", result, err)
+		}
+	}
+}
+
+func TestDistanceOnly(t *testing.T) {
+	long := strings.Repeat("abcdefgh", 9)
+	pairs := append([][2]string{
+		{"", ""},
+		{"", "héllo"},
+		{"h\xffllo", "hello"},
+		{long, long[1:]},
+		{long[:64], long[:63] + "x"},
+		{long[:65], long[:64]},
+	}, benchmarkPairs...)
+	for _, pair := range pairs {
+		for _, options := range [][]levenshtein.Option{nil, {levenshtein.SetSwapCost(2)}} {
+			expected := levenshtein.Distance(pair[0], pair[1], options...)
+			if dist := levenshtein.DistanceOnly(pair[0], pair[1], options...); dist != expected {
+				t.Errorf("DistanceOnly(%q, %q) = %d, expected %d", pair[0], pair[1], dist, expected)
+			}
+		}
+	}
+}
+
+func BenchmarkDistanceOnly(b *testing.B) {
+	b.Run("short", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, pair := range benchmarkPairs {
+				levenshtein.DistanceOnly(pair[0], pair[1])
+			}
+		}
+	})
+	b.Run("long", func(b *testing.B) {
+		source, target := strings.Repeat("kitten", 20), strings.Repeat("sitting", 20)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			levenshtein.DistanceOnly(source, target)
+		}
+	})
+}
+
+func TestIgnoreSubstrings(t *testing.T) {
+	tests := []struct {
+		subs           []string
+		source, target string
+		expected       int
+	}{
+		{[]string{" (New)"}, "Acme Widget (New)", "Acme Widget", 0},
+		{[]string{" (New)"}, "Acme (New) Widget (New)", "Acme Widget", 0},
+		{[]string{"(New)"}, "Acme Widget (New)", "Acme Widget", 1}, // The space is not removed
+		{[]string{}, "abc", "ab", 1},
+		{[]string{""}, "abc", "ab", 1},
+		// Overlapping occurrences: the longest substring is removed first
+		{[]string{"ab", "abc"}, "abcd", "d", 0},
+		{[]string{"abc", "ab"}, "abcd", "d", 0},
+		{[]string{"ab", "bcd"}, "abcd", "", 2}, // "ab" matches first, leaving "cd"
+		// Removals which create new occurrences are not repeated
+		{[]string{"ab"}, "aabb", "", 2},
+	}
+	for _, test := range tests {
+		option := levenshtein.IgnoreSubstrings(test.subs...)
+		if dist := levenshtein.Distance(test.source, test.target, option); dist != test.expected {
+			t.Errorf("Distance(%q, %q, IgnoreSubstrings(%q)) = %d, expected %d", test.source, test.target, test.subs, dist, test.expected)
+		}
+	}
+}
+
+func TestSlugNormalize(t *testing.T) {
+	tests := []struct {
+		source, target string
+		expected       int
+	}{
+		{"Hello, World!", "hello-world", 0},
+		{"  Hello   World  ", "hello-world", 0},
+		{"hello--world", "hello_world", 0},
+		{"Café au Lait", "cafe-au-lait", 0},
+		{"naïve façade", "naive-facade", 0},
+		{"ÅNGSTRÖM", "angstrom", 0},
+		{"Straße", "strasse", 2}, // ß is a letter, which is kept
+		{"Москва", "москва", 0},
+		{"v1.2.3", "v1-2-3", 0},
+		{"---", "", 0},
+		{"hello world", "hello-worlds", 1},
+		// Separators are significant, even though their type is not
+		{"helloworld", "hello world", 1},
+	}
+	for _, test := range tests {
+		if dist := levenshtein.Distance(test.source, test.target, levenshtein.SlugNormalize()); dist != test.expected {
+			t.Errorf("Distance(%q, %q, SlugNormalize()) = %d, expected %d", test.source, test.target, dist, test.expected)
+		}
+	}
+
+	ops := levenshtein.Operations("Hello, World!", "hello-worlds", levenshtein.SlugNormalize())
+	if result := ops[len(ops)-1].Result; result != "hello-worlds" {
+		t.Errorf("Operations() final result = %q, expected the normalized target", result)
+	}
+}
+
+func TestCellWidths(t *testing.T) {
+	source, target := strings.Repeat("a", 100), strings.Repeat("b", 100)
+	// The largest possible cell is the cost of inserting and removing every
+	// character: 32600 fits in an int16, but 32800 does not
+	for _, cost := range []int{163, 164} {
+		options := []levenshtein.Option{
+			levenshtein.SetInsertCost(cost),
+			levenshtein.SetRemoveCost(cost),
+			levenshtein.SetSwapCost(2*cost + 1),
+		}
+		narrow := levenshtein.Build(source, target, options...)
+
+		// A swap cost function forces the cells to be stored as int
+		wide := levenshtein.Build(source, target, append(options, levenshtein.SetSwapCostFunc(func(a, b rune) int {
+			return 2*cost + 1
+		}))...)
+
+		if got, want := narrow.Distance(), 2*100*cost; got != want {
+			t.Errorf("cost %d: Distance() = %d, expected %d", cost, got, want)
+		}
+		if got, want := narrow.Operations(), wide.Operations(); !reflect.DeepEqual(got, want) {
+			t.Errorf("cost %d: Operations() = %v, expected %v", cost, got, want)
+		}
+		if got, want := narrow.String(), wide.String(); got != want {
+			t.Errorf("cost %d: String() = %q, expected %q", cost, got, want)
+		}
+	}
+}
+
+func BenchmarkBuildMemory(b *testing.B) {
+	source, target := strings.Repeat("kitten", 200), strings.Repeat("sitting", 200)
+	for _, bench := range []struct {
+		name    string
+		options []levenshtein.Option
+	}{
+		{"Narrow", nil},
+		{"Wide", []levenshtein.Option{levenshtein.SetSwapCostFunc(func(a, b rune) int { return 1 })}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				levenshtein.Build(source, target, bench.options...)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		source, target string
+		expected       string
+	}{
+		{"horse", "arose", "3 edits: 1 substitution (h→a), 1 deletion (o), 1 insertion (o)"},
+		{"horse", "horse", "no edits"},
+		{"", "", "no edits"},
+		{"cat", "cats", "1 edit: 1 insertion (s)"},
+		{"kitten", "sitting", "3 edits: 2 substitutions (k→s, e→i), 1 insertion (g)"},
+		{"abc", "", "3 edits: 3 deletions (a, b, c)"},
+	}
+	for _, test := range tests {
+		if got := levenshtein.Build(test.source, test.target).Explain(); got != test.expected {
+			t.Errorf("Build(%q, %q).Explain() = %q, expected %q", test.source, test.target, got, test.expected)
+		}
+	}
+}
+
+func TestCappedDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		cap      int
+		options  []levenshtein.Option
+		expected int
+	}{
+		{"horse", "arose", 10, nil, 3},
+		{"horse", "arose", 3, nil, 3},
+		{"horse", "arose", 2, nil, 2},
+		{"horse", "arose", 1, nil, 1},
+		{"horse", "arose", 0, nil, 0},
+		{"horse", "horse", 0, nil, 0},
+		{"", "abcdef", 4, nil, 4},
+		{"abc", strings.Repeat("x", 100), 5, nil, 5},
+		{"horse", "arose", 5, []levenshtein.Option{levenshtein.SetSwapCost(3)}, 4},
+		{"horse", "arose", 2, []levenshtein.Option{levenshtein.SetAffineGap(2, 1)}, 2},
+		{"horse", "arose", 4, []levenshtein.Option{levenshtein.SetAffineGap(2, 1)}, 3},
+		{"rnodern", "modern", 2, []levenshtein.Option{levenshtein.ConfusionPairs(map[string]string{"rn": "m"}, 0)}, 0},
+		// Negative costs rule out the bound from the difference in length
+		{"bcbéaaa", "b", 6, []levenshtein.Option{levenshtein.SetSwapCost(-1)}, 5},
+		// Unreachable targets are always at the cap
+		{"abc", "", -1, []levenshtein.Option{levenshtein.MaxRemoves(1)}, -1},
+		{"abc", "", 0, []levenshtein.Option{levenshtein.MaxRemoves(1)}, 0},
+		{"abc", "", 1, []levenshtein.Option{levenshtein.MaxRemoves(1)}, 1},
+		{"abc", "", 5, []levenshtein.Option{levenshtein.MaxRemoves(1)}, 5},
+	}
+	for _, test := range tests {
+		if dist := levenshtein.CappedDistance(test.a, test.b, test.cap, test.options...); dist != test.expected {
+			t.Errorf("CappedDistance(%q, %q, %d) = %d, expected %d", test.a, test.b, test.cap, dist, test.expected)
+		}
+	}
+
+	// The capped distance always agrees with the full distance
+	words := []string{"", "a", "kitten", "sitting", "saturday", "sunday", "abcdefgh"}
+	for _, a := range words {
+		for _, b := range words {
+			dist := levenshtein.Distance(a, b)
+			for cap := 0; cap <= 9; cap++ {
+				expected := dist
+				if cap < expected {
+					expected = cap
+				}
+				if got := levenshtein.CappedDistance(a, b, cap); got != expected {
+					t.Errorf("CappedDistance(%q, %q, %d) = %d, expected %d", a, b, cap, got, expected)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkCappedDistance(b *testing.B) {
+	source, target := strings.Repeat("abcdefghij", 50), strings.Repeat("klmnopqrst", 50)
+	b.Run("Distance", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			levenshtein.Distance(source, target)
+		}
+	})
+	b.Run("CappedDistance", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			levenshtein.CappedDistance(source, target, 10)
+		}
+	})
+}
+
+func TestCSV(t *testing.T) {
+	m := levenshtein.Build("horse", "arose")
+	expected := strings.Join([]string{
+		",,a,r,o,s,e",
+		",0,1,2,3,4,5",
+		"h,1,1,2,3,4,5",
+		"o,2,2,2,2,3,4",
+		"r,3,3,2,3,3,4",
+		"s,4,4,3,3,3,4",
+		"e,5,5,4,4,4,3",
+	}, "\n") + "\n"
+	if csv := m.CSV(); csv != expected {
+		t.Errorf("CSV() = %q, expected %q", csv, expected)
+	}
+
+	var b strings.Builder
+	if err := m.WriteCSV(&b, false); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	expected = "0,1,2,3,4,5\n1,1,2,3,4,5\n2,2,2,2,3,4\n3,3,2,3,3,4\n4,4,3,3,3,4\n5,5,4,4,4,3\n"
+	if csv := b.String(); csv != expected {
+		t.Errorf("WriteCSV(false) = %q, expected %q", csv, expected)
+	}
+
+	// Characters with a special meaning in CSV are quoted
+	expected = ",,\",\",\"\"\"\"\n,0,1,2\na,1,1,2\n"
+	if csv := levenshtein.Build("a", `,"`).CSV(); csv != expected {
+		t.Errorf("CSV() = %q, expected %q", csv, expected)
+	}
+}
+
+func TestNumericDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"007", "7", 0},
+		{"1,234", "1234", 0},
+		{"1,234", "1.234", 0},
+		{"+1 (555) 123-4567", "15551234567", 0},
+		{"0042", "0043", 1},
+		{"1,234", "12,345", 1},
+		{"700", "7", 2}, // Trailing zeros are significant
+		{"000", "0", 0},
+		{"0", "", 1},
+		{"n/a", "", 0},
+		{"0", "abc", 1},
+		{"", "", 0},
+		{"١٢٣", "12٣", 2}, // Arabic-Indic digits are digits, but not the same digits
+	}
+	for _, test := range tests {
+		if dist := levenshtein.NumericDistance(test.a, test.b); dist != test.expected {
+			t.Errorf("NumericDistance(%q, %q) = %d, expected %d", test.a, test.b, dist, test.expected)
+		}
+	}
+}
+
+func TestDistanceLowerBound(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "abc", 0},
+		{"abc", "cba", 0}, // Same characters, different order
+		{"horse", "arose", 1},
+		{"kitten", "sitting", 3},
+		{"", "héllo", 5},
+		{"aaaa", "a", 3},
+		{"abc", "xyz", 3},
+	}
+	for _, test := range tests {
+		if bound := levenshtein.DistanceLowerBound(test.a, test.b); bound != test.expected {
+			t.Errorf("DistanceLowerBound(%q, %q) = %d, expected %d", test.a, test.b, bound, test.expected)
+		}
+	}
+
+	// The bound never exceeds the true distance
+	rnd := rand.New(rand.NewSource(1))
+	word := func() string {
+		runes := make([]rune, rnd.Intn(12))
+		for i := range runes {
+			runes[i] = []rune("abcdé")[rnd.Intn(5)]
+		}
+		return string(runes)
+	}
+	for i := 0; i < 5000; i++ {
+		a, b := word(), word()
+		bound, dist := levenshtein.DistanceLowerBound(a, b), levenshtein.Distance(a, b)
+		if bound > dist {
+			t.Fatalf("DistanceLowerBound(%q, %q) = %d, greater than Distance = %d", a, b, bound, dist)
+		}
+		if diff := len([]rune(a)) - len([]rune(b)); bound < diff || bound < -diff {
+			t.Fatalf("DistanceLowerBound(%q, %q) = %d, less than the length difference %d", a, b, bound, diff)
+		}
+	}
+}
+
+func TestBagDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"listen", "silent", 0},
+		{"abc", "abd", 1},
+		{"aab", "abb", 1},
+		{"book", "back", 2},
+		{"abc", "", 3},
+		{"ab", "abcde", 3},
+		{"naïve", "naive", 1},
+	}
+	for _, test := range tests {
+		if dist := levenshtein.BagDistance(test.a, test.b); dist != test.expected {
+			t.Errorf("BagDistance(%q, %q) = %d, expected %d", test.a, test.b, dist, test.expected)
+		}
+		if dist := levenshtein.BagDistance(test.b, test.a); dist != test.expected {
+			t.Errorf("BagDistance(%q, %q) = %d, expected %d", test.b, test.a, dist, test.expected)
+		}
+	}
+
+	// The bag distance never exceeds the edit distance
+	rnd := rand.New(rand.NewSource(2))
+	word := func() string {
+		runes := make([]rune, rnd.Intn(10))
+		for i := range runes {
+			runes[i] = []rune("abcxyz")[rnd.Intn(6)]
+		}
+		return string(runes)
+	}
+	for i := 0; i < 5000; i++ {
+		a, b := word(), word()
+		if bag, dist := levenshtein.BagDistance(a, b), levenshtein.Distance(a, b); bag > dist {
+			t.Fatalf("BagDistance(%q, %q) = %d, greater than Distance = %d", a, b, bag, dist)
+		}
+	}
+}
+
+func TestKeystrokeDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		// The same edit distance, but the edit at the end takes more
+		// keystrokes to reach
+		{"abcdef", "xbcdef", 2}, // Delete, type
+		{"abcdef", "abcdex", 7}, // Five moves, delete, type
+		{"abcdef", "bcdef", 1},  // Delete
+		{"abcdef", "abcde", 6},  // Five moves, delete
+		{"abcdef", "abcdef", 0},
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"abc", "abcx", 4},    // Three moves, type
+		{"horse", "arose", 5}, // Type a, delete h and o, move past r, type o
+		{"héllo", "hallo", 3}, // Move, delete, type
+	}
+	for _, test := range tests {
+		if keystrokes := levenshtein.KeystrokeDistance(test.a, test.b); keystrokes != test.expected {
+			t.Errorf("KeystrokeDistance(%q, %q) = %d, expected %d", test.a, test.b, keystrokes, test.expected)
+		}
+	}
+
+	for _, pair := range [][2]string{{"abcdef", "xbcdef"}, {"abcdef", "abcdex"}} {
+		if dist := levenshtein.Distance(pair[0], pair[1]); dist != 1 {
+			t.Errorf("Distance(%q, %q) = %d, expected 1", pair[0], pair[1], dist)
+		}
+	}
+}
+
+func TestRelationship(t *testing.T) {
+	tests := []struct {
+		source, target string
+		options        []levenshtein.Option
+		expected       levenshtein.Relationship
+	}{
+		{"", "", nil, levenshtein.Identical},
+		{"abc", "abc", nil, levenshtein.Identical},
+		{"ABC", "abc", []levenshtein.Option{levenshtein.IgnoreCase()}, levenshtein.Identical},
+		{"abc", "abcdef", nil, levenshtein.Insertion}, // Appended
+		{"abc", "xyzabc", nil, levenshtein.Insertion}, // Prefixed
+		{"abc", "aXbYc", nil, levenshtein.Insertion},
+		{"", "abc", nil, levenshtein.Insertion},
+		{"abcdef", "abc", nil, levenshtein.Deletion},
+		{"abc", "", nil, levenshtein.Deletion},
+		{"aXbYc", "abc", nil, levenshtein.Deletion},
+		{"abc", "abd", nil, levenshtein.Mixed},
+		{"abc", "bcd", nil, levenshtein.Mixed}, // A removal and an insertion
+		{"horse", "arose", nil, levenshtein.Mixed},
+	}
+	for _, test := range tests {
+		if rel := levenshtein.Build(test.source, test.target, test.options...).Relationship(); rel != test.expected {
+			t.Errorf("Build(%q, %q).Relationship() = %s, expected %s", test.source, test.target, rel, test.expected)
+		}
+	}
+}
+
+func TestAllOperationsLimited(t *testing.T) {
+	tests := []struct {
+		source, target string
+		maxPaths       int
+		count          int
+		truncated      bool
+	}{
+		{"", "", 10, 1, false},
+		{"abc", "abc", 10, 1, false},
+		{"kitten", "sitting", 10, 1, false},
+		{"horse", "arose", 10, 5, false},
+		{"ab", "ba", 10, 3, false}, // SS, RKI, and IKR
+		{"ab", "ba", 3, 3, false},
+		{"ab", "ba", 2, 2, true},
+		{"ab", "ba", 0, 0, true},
+		{"ab", "ba", -1, 0, true},
+		{"", "", 0, 0, true},
+		{"", "", -1, 0, true},
+		// There are C(20, 10) ways of choosing which characters to insert
+		{strings.Repeat("a", 10), strings.Repeat("a", 20), 100, 100, true},
+	}
+	for _, test := range tests {
+		matrix := levenshtein.Build(test.source, test.target)
+		paths, truncated := matrix.AllOperationsLimited(test.maxPaths)
+		if len(paths) != test.count || truncated != test.truncated {
+			t.Errorf("AllOperationsLimited(%q, %q, %d) returned %d paths, truncated %t; expected %d, %t",
+				test.source, test.target, test.maxPaths, len(paths), truncated, test.count, test.truncated)
+		}
+		if len(paths) > 0 && !reflect.DeepEqual(paths[0], matrix.Operations()) {
+			t.Errorf("AllOperationsLimited(%q, %q) first path = %v, expected Operations() = %v", test.source, test.target, paths[0], matrix.Operations())
+		}
+
+		seen := map[string]bool{}
+		for _, ops := range paths {
+			key := fmt.Sprint(ops)
+			if seen[key] {
+				t.Errorf("AllOperationsLimited(%q, %q) returned %v more than once", test.source, test.target, ops)
+			}
+			seen[key] = true
+			if result, err := levenshtein.Apply(test.source, ops); err != nil || result != test.target {
+				t.Errorf("Apply(%q, %v) = %q, %v; expected %q", test.source, ops, result, err, test.target)
+			}
+			if err := levenshtein.VerifyResults(ops); err != nil {
+				t.Errorf("VerifyResults(%v): %v", ops, err)
+			}
+			if len(ops) > 0 && ops[len(ops)-1].CumulativeCost != matrix.Distance() {
+				t.Errorf("AllOperationsLimited(%q, %q) path %v has cost %d, expected %d", test.source, test.target, ops, ops[len(ops)-1].CumulativeCost, matrix.Distance())
+			}
+		}
+	}
+
+	// Options which change the shape of the matrix are not enumerated
+	matrix := levenshtein.Build("ab", "ba", levenshtein.SetAffineGap(3, 1))
+	if paths, truncated := matrix.AllOperationsLimited(10); len(paths) != 1 || !truncated {
+		t.Errorf("AllOperationsLimited with SetAffineGap returned %d paths, truncated %t; expected 1, true", len(paths), truncated)
+	}
+	if paths, truncated := matrix.AllOperationsLimited(-1); len(paths) != 0 || !truncated {
+		t.Errorf("AllOperationsLimited(-1) with SetAffineGap returned %d paths, truncated %t; expected 0, true", len(paths), truncated)
+	}
+}
+
+func TestSubstitutionRate(t *testing.T) {
+	tests := []struct {
+		source, target string
+		options        []levenshtein.Option
+		expected       float64
+	}{
+		{"", "", nil, 0},
+		{"same", "same", nil, 0},
+		{"cat", "cut", nil, 1},
+		{"kitten", "sitting", nil, 2.0 / 3},
+		{"color", "colour", nil, 0},
+		{"abcdef", "abef", nil, 0},
+		{"horse", "arose", nil, 1.0 / 3},
+		// Expensive swaps are replaced by insertions and removals
+		{"cat", "cut", []levenshtein.Option{levenshtein.SetSwapCost(3)}, 0},
+	}
+	for _, test := range tests {
+		rate := levenshtein.Build(test.source, test.target, test.options...).SubstitutionRate()
+		if math.Abs(rate-test.expected) > 1e-9 {
+			t.Errorf("SubstitutionRate(%q, %q) = %v, expected %v", test.source, test.target, rate, test.expected)
+		}
+	}
+}
+
+func TestIsCaseOnly(t *testing.T) {
+	tests := []struct {
+		source, target string
+		options        []levenshtein.Option
+		expected       []bool // IsCaseOnly of each swap, in order
+	}{
+		{"Horse", "horse", nil, []bool{true}},
+		{"Horse", "house", nil, []bool{true, false}},
+		{"ÉCOLE", "école", nil, []bool{true, true, true, true, true}},
+		{"horse", "worse", nil, []bool{false}},
+		{"a", "1", nil, []bool{false}},
+		// Case-insensitive comparisons have no case-only swaps to report
+		{"Horse", "horse", []levenshtein.Option{levenshtein.IgnoreCase()}, nil},
+		{"Horse", "horsE", []levenshtein.Option{levenshtein.SetAffineGap(3, 1)}, []bool{true, true}},
+	}
+	for _, test := range tests {
+		var flags []bool
+		for _, op := range levenshtein.Operations(test.source, test.target, test.options...) {
+			if op.Type == levenshtein.Swap {
+				flags = append(flags, op.IsCaseOnly)
+			} else if op.IsCaseOnly {
+				t.Errorf("Operations(%q, %q): %v has IsCaseOnly set, but is not a swap", test.source, test.target, op)
+			}
+		}
+		if !reflect.DeepEqual(flags, test.expected) {
+			t.Errorf("Operations(%q, %q) IsCaseOnly of swaps = %v, expected %v", test.source, test.target, flags, test.expected)
+		}
+	}
+
+	// Inverting an operation preserves the flag
+	ops := levenshtein.Operations("Horse", "horse")
+	if inv := levenshtein.Invert(ops); !inv[len(inv)-1].IsCaseOnly {
+		t.Errorf("Invert(%v) = %v, expected a case-only swap", ops, inv)
+	}
+}
+
+func TestDistanceFromOffset(t *testing.T) {
+	tests := []struct {
+		a, b         string
+		commonPrefix int
+		options      []levenshtein.Option
+	}{
+		{"", "", 0, nil},
+		{"abc", "abc", 3, nil},
+		{"prefix-kitten", "prefix-sitting", 7, nil},
+		{"prefix-kitten", "prefix-sitting", 3, nil},
+		{"héllo wörld", "héllo world", 6, nil},
+		// The prefix is validated, so an overestimate is harmless
+		{"prefix-kitten", "prefix-sitting", 10, nil},
+		{"abc", "xyz", 3, nil},
+		{"abc", "ab", 5, nil},
+		{"abc", "abc", -1, nil},
+		{"Prefix-kitten", "PREFIX-sitting", 7, []levenshtein.Option{levenshtein.IgnoreCase()}},
+		{"prefix-kitten", "prefix-sitting", 7, []levenshtein.Option{levenshtein.SetSwapCost(3), levenshtein.SetInsertCost(2)}},
+		// Options which make the prefix matter disable the optimization
+		{"ab", "abb", 2, []levenshtein.Option{levenshtein.SetPositionWeights([]float64{1, 1, 0})}},
+		{"ab", "ab", 2, []levenshtein.Option{levenshtein.FirstCharWeight(3)}},
+		{"abcdef", "abef", 2, []levenshtein.Option{levenshtein.SetAffineGap(3, 1)}},
+	}
+	for _, test := range tests {
+		expected := levenshtein.Distance(test.a, test.b, test.options...)
+		if dist := levenshtein.DistanceFromOffset(test.a, test.b, test.commonPrefix, test.options...); dist != expected {
+			t.Errorf("DistanceFromOffset(%q, %q, %d) = %d, expected %d", test.a, test.b, test.commonPrefix, dist, expected)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(3))
+	word := func() string {
+		runes := make([]rune, rnd.Intn(10))
+		for i := range runes {
+			runes[i] = []rune("abc")[rnd.Intn(3)]
+		}
+		return string(runes)
+	}
+	for i := 0; i < 2000; i++ {
+		prefix := word()
+		a, b := prefix+word(), prefix+word()
+		options := []levenshtein.Option{
+			levenshtein.SetInsertCost(rnd.Intn(4)),
+			levenshtein.SetRemoveCost(rnd.Intn(4)),
+			levenshtein.SetSwapCost(rnd.Intn(4)),
+		}
+		expected := levenshtein.Distance(a, b, options...)
+		if dist := levenshtein.DistanceFromOffset(a, b, len(prefix), options...); dist != expected {
+			t.Fatalf("DistanceFromOffset(%q, %q, %d) = %d, expected %d", a, b, len(prefix), dist, expected)
+		}
+	}
+}
+
+func TestMinCost(t *testing.T) {
+	tests := []struct {
+		insert, remove, swap int
+		cost                 int
+		which                levenshtein.OpType
+	}{
+		{1, 2, 3, 1, levenshtein.Insert},
+		{2, 1, 3, 1, levenshtein.Remove},
+		{3, 2, 1, 1, levenshtein.Swap},
+		{5, 5, 0, 0, levenshtein.Swap},
+		{-1, 0, 0, -1, levenshtein.Insert},
+		// Ties go to insertions, then removals
+		{1, 1, 1, 1, levenshtein.Insert},
+		{2, 1, 1, 1, levenshtein.Remove},
+		{1, 2, 1, 1, levenshtein.Insert},
+	}
+	for _, test := range tests {
+		cost, which := levenshtein.MinCost(test.insert, test.remove, test.swap)
+		if cost != test.cost || which != test.which {
+			t.Errorf("MinCost(%d, %d, %d) = %d, %s; expected %d, %s", test.insert, test.remove, test.swap, cost, which, test.cost, test.which)
+		}
+	}
+}
+
+func TestWordBoundaryBonus(t *testing.T) {
+	tests := []struct {
+		source, target string
+		bonus          int
+		dist           int
+		ops            string
+	}{
+		// Without a bonus, the characters of different words are swapped
+		{"big red dog", "red big dog", 0, 6, "SSSKSSSKKKK"},
+		// With a bonus, "red " is kept intact, and "big " is moved
+		{"big red dog", "red big dog", 2, 8 - 2*2, "RRRRKKKKIIIIKKK"},
+		{"ab cd", "cd ab", 2, 6 - 2, "RRRKKIII"},
+		// Identical phrases have a negative distance
+		{"a b", "a b", 1, -2, "KKK"},
+		{"", "", 1, 0, ""},
+		// Only characters which start a word in both strings get the bonus
+		{"ab", "xab", 1, 1, "IKK"},
+	}
+	for _, test := range tests {
+		matrix := levenshtein.Build(test.source, test.target, levenshtein.WordBoundaryBonus(test.bonus))
+		if dist := matrix.Distance(); dist != test.dist {
+			t.Errorf("%q, %q: Distance() = %d, expected %d", test.source, test.target, dist, test.dist)
+		}
+		ops := matrix.Operations()
+		var types []byte
+		for _, op := range ops {
+			types = append(types, "IRKS"[op.Type])
+		}
+		if string(types) != test.ops {
+			t.Errorf("%q, %q: Operations() types = %q, expected %q", test.source, test.target, types, test.ops)
+		}
+		if err := levenshtein.VerifyResults(ops); err != nil {
+			t.Errorf("%q, %q: VerifyResults(Operations()): %v", test.source, test.target, err)
+		}
+	}
+
+	// Later rows of the matrix can be cheaper than earlier ones, so
+	// comparisons against a maximum distance must not stop early
+	bonus := levenshtein.WordBoundaryBonus(2)
+	if dist := levenshtein.Distance("xx a b", "a b", bonus); dist != -1 {
+		t.Errorf("Distance(\"xx a b\", \"a b\") = %d, expected -1", dist)
+	}
+	if !levenshtein.Compile("xx a b", bonus).Within("a b", 1) {
+		t.Errorf("Within(\"xx a b\", \"a b\", 1) = false, expected true")
+	}
+	if dist := levenshtein.CappedDistance("xx a b", "a b", 1, bonus); dist != -1 {
+		t.Errorf("CappedDistance(\"xx a b\", \"a b\", 1) = %d, expected -1", dist)
+	}
+	if matches := levenshtein.WithinDistance("a b", []string{"xx a b", "a b c"}, 0, bonus); len(matches) != 2 {
+		t.Errorf("WithinDistance(\"a b\") = %v, expected both candidates", matches)
+	}
+}
+
+func TestSourceEditDensity(t *testing.T) {
+	tests := []struct {
+		source, target string
+		expected       []int
+	}{
+		{"", "", []int{}},
+		{"", "abc", []int{}},
+		{"abc", "abc", []int{0, 0, 0}},
+		{"abc", "", []int{1, 1, 1}},
+		// h is swapped, o is removed, and the inserted o follows r
+		{"horse", "arose", []int{1, 1, 1, 0, 0}},
+		{"kitten", "sitting", []int{1, 0, 0, 0, 1, 1}},
+		// Insertions before the first character are attributed to it
+		{"cat", "the cat", []int{4, 0, 0}},
+		// A run of insertions accumulates on the preceding character
+		{"cat", "cats and dogs", []int{0, 0, 10}},
+		{"héllo", "hello", []int{0, 1, 0, 0, 0}},
+	}
+	for _, test := range tests {
+		density := levenshtein.Build(test.source, test.target).SourceEditDensity()
+		if !reflect.DeepEqual(density, test.expected) {
+			t.Errorf("SourceEditDensity(%q, %q) = %v, expected %v", test.source, test.target, density, test.expected)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package levenshtein
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+)
+
+// BuildEncoding builds a Matrix from source and target strings which are
+// encoded in the given encoding (e.g. charmap.ISO8859_1 or japanese.ShiftJIS)
+// rather than UTF-8, decoding both to runes before building it. The decoders
+// in golang.org/x/text replace malformed input with the Unicode replacement
+// character (U+FFFD) rather than failing, so the strings are treated as
+// malformed if either decodes to one, and an error is returned.
+func BuildEncoding(source, target string, enc encoding.Encoding, options ...Option) (*Matrix, error) {
+	decodedSource, err := decode(source, enc)
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+	decodedTarget, err := decode(target, enc)
+	if err != nil {
+		return nil, fmt.Errorf("target: %w", err)
+	}
+	return Build(decodedSource, decodedTarget, options...), nil
+}
+
+// decode decodes a string from the given encoding to UTF-8.
+func decode(s string, enc encoding.Encoding) (string, error) {
+	decoded, err := enc.NewDecoder().String(s)
+	if err != nil {
+		return "", err
+	}
+	if i := strings.IndexRune(decoded, utf8.RuneError); i >= 0 {
+		return "", fmt.Errorf("malformed input at character %d", utf8.RuneCountInString(decoded[:i]))
+	}
+	return decoded, nil
+}
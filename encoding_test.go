@@ -0,0 +1,52 @@
+package levenshtein_test
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func TestBuildEncoding(t *testing.T) {
+	tests := []struct {
+		source, target string
+		enc            encoding.Encoding
+		expected       int
+	}{
+		// "café" and "cafe" in Latin-1: é is the single byte 0xE9
+		{"caf\xe9", "cafe", charmap.ISO8859_1, 1},
+		{"caf\xe9", "caf\xe9", charmap.ISO8859_1, 0},
+		// "résumé" and "resume"
+		{"r\xe9sum\xe9", "resume", charmap.ISO8859_1, 2},
+		// "あい" and "あう" in Shift-JIS: each character is two bytes
+		{"\x82\xa0\x82\xa2", "\x82\xa0\x82\xa4", japanese.ShiftJIS, 1},
+	}
+	for _, test := range tests {
+		matrix, err := levenshtein.BuildEncoding(test.source, test.target, test.enc)
+		if err != nil {
+			t.Errorf("BuildEncoding(%q, %q): %v", test.source, test.target, err)
+			continue
+		}
+		if dist := matrix.Distance(); dist != test.expected {
+			t.Errorf("BuildEncoding(%q, %q).Distance() = %d, expected %d", test.source, test.target, dist, test.expected)
+		}
+	}
+
+	// Decoding the Latin-1 bytes as UTF-8 would corrupt them
+	if dist := levenshtein.Distance("caf\xe9", "caf\xe8"); dist != 0 {
+		t.Errorf("Distance of invalid UTF-8 = %d, expected 0", dist)
+	}
+
+	// A lone lead byte is malformed Shift-JIS
+	for _, test := range []struct{ source, target string }{
+		{"\x82", "a"},
+		{"a", "\x82\xa0\x82"},
+	} {
+		if _, err := levenshtein.BuildEncoding(test.source, test.target, japanese.ShiftJIS); err == nil {
+			t.Errorf("BuildEncoding(%q, %q) succeeded, expected error", test.source, test.target)
+		}
+	}
+}
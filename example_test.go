@@ -2,6 +2,8 @@ package levenshtein_test
 
 import (
 	"fmt"
+	"strings"
+	"unicode"
 
 	"github.com/nathanjcochran/levenshtein"
 )
@@ -92,3 +94,830 @@ func ExampleOperations() {
 	//   keep s at index 3: arose
 	//   keep e at index 4: arose
 }
+
+func ExampleSetResultMode() {
+	for _, mode := range []levenshtein.ResultMode{
+		levenshtein.ResultFull,
+		levenshtein.ResultFinalOnly,
+		levenshtein.ResultNone,
+	} {
+		var results []string
+		for _, op := range levenshtein.Operations("kitten", "sitting", levenshtein.SetResultMode(mode)) {
+			results = append(results, op.Result)
+		}
+		fmt.Printf("%q\n", results)
+	}
+
+	// Output:
+	// ["sitten" "sitten" "sitten" "sitten" "sittin" "sittin" "sitting"]
+	// ["" "" "" "" "" "" "sitting"]
+	// ["" "" "" "" "" "" ""]
+}
+
+func ExampleQWERTYSwapCost() {
+	options := []levenshtein.Option{
+		levenshtein.SetInsertCost(levenshtein.QWERTYDefaultCost),
+		levenshtein.SetRemoveCost(levenshtein.QWERTYDefaultCost),
+		levenshtein.SetSwapCostFunc(levenshtein.QWERTYSwapCost),
+	}
+	fmt.Println(levenshtein.Distance("hellp", "hello", options...))
+	fmt.Println(levenshtein.Distance("hellx", "hello", options...))
+
+	// Output:
+	// 1
+	// 2
+}
+
+func ExampleApply() {
+	ops := levenshtein.Operations("horse", "arose")
+	result, err := levenshtein.Apply("horse", ops)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(result)
+
+	// Output:
+	// arose
+}
+
+func ExampleScore() {
+	fmt.Println(levenshtein.Score("horse", "arose", 3))
+	fmt.Println(levenshtein.Score("horse", "arose", 2))
+	fmt.Println(levenshtein.Score("horse", "horse", 0))
+	fmt.Println(levenshtein.Score("", "", 0))
+
+	// Output:
+	// true 0.4
+	// false 0
+	// true 1
+	// true 1
+}
+
+func ExampleCombiningMarksCost() {
+	// Decomposed form of "résumé", using the combining acute accent (U+0301)
+	decomposed := "re\u0301sume\u0301"
+	fmt.Println(levenshtein.Distance("resume", decomposed))
+	fmt.Println(levenshtein.Distance("resume", decomposed, levenshtein.CombiningMarksCost(0)))
+	fmt.Println(levenshtein.Distance("resume", "rasume", levenshtein.CombiningMarksCost(0)))
+
+	// Output:
+	// 2
+	// 0
+	// 1
+}
+
+func ExampleDistanceToSet() {
+	synonyms := []string{"automobile", "car", "vehicle", "motorcar"}
+	fmt.Println(levenshtein.DistanceToSet("cart", synonyms))
+	fmt.Println(levenshtein.DistanceToSet("cart", nil))
+
+	// Output:
+	// 1
+	// -1
+}
+
+func ExampleOperation_cumulativeCost() {
+	for _, op := range levenshtein.Operations("horse", "arose") {
+		fmt.Printf("%6s %c: %d\n", op.Type, op.Char, op.CumulativeCost)
+	}
+
+	// Output:
+	//   swap a: 1
+	// remove o: 2
+	//   keep r: 2
+	// insert o: 3
+	//   keep s: 3
+	//   keep e: 3
+}
+
+func ExampleFirstDifference() {
+	fmt.Println(levenshtein.FirstDifference("horse", "horse"))
+	fmt.Println(levenshtein.FirstDifference("horse", "house"))
+	fmt.Println(levenshtein.FirstDifference("horse", "horses"))
+	fmt.Println(levenshtein.FirstDifference("héllo", "hélp"))
+	fmt.Println(levenshtein.FirstDifference("", ""))
+
+	// Output:
+	// 5 true
+	// 2 false
+	// 5 false
+	// 3 false
+	// 0 true
+}
+
+func ExampleWildcardRune() {
+	wildcard := levenshtein.WildcardRune('?')
+	fmt.Println(levenshtein.Distance("a?c", "axc", wildcard))
+	fmt.Println(levenshtein.Distance("abc", "a?c", wildcard))
+	fmt.Println(levenshtein.Distance("a?c", "axy", wildcard))
+	for _, op := range levenshtein.Operations("a?c", "axc", wildcard) {
+		fmt.Println(op)
+	}
+
+	// Output:
+	// 0
+	// 0
+	// 1
+	//   keep a at index 0: a?c
+	//   swap x at index 1: axc
+	//   keep c at index 2: axc
+}
+
+func ExampleIgnoreRunes() {
+	fmt.Println(levenshtein.Distance("(555) 123-4567", "5551234567"))
+	fmt.Println(levenshtein.Distance("(555) 123-4567", "5551234567", levenshtein.IgnoreRunes('(', ')', ' ', '-')))
+
+	// Output:
+	// 4
+	// 0
+}
+
+func ExampleIgnoreFunc() {
+	ignore := levenshtein.IgnoreFunc(func(r rune) bool {
+		return !unicode.IsDigit(r)
+	})
+	fmt.Println(levenshtein.Distance("+1 (555) 123-4567", "555.123.4568", ignore))
+	for _, op := range levenshtein.Operations("+1 (555) 123", "555.124", ignore) {
+		fmt.Println(op)
+	}
+
+	// Output:
+	// 2
+	// remove 1 at index 0: 555123
+	//   keep 5 at index 0: 555123
+	//   keep 5 at index 1: 555123
+	//   keep 5 at index 2: 555123
+	//   keep 1 at index 3: 555123
+	//   keep 2 at index 4: 555123
+	//   swap 4 at index 5: 555124
+}
+
+func ExampleLineOperations() {
+	source := "one\ntwo\nthree\n"
+	target := "one\n2\nthree\nfour\n"
+	for _, op := range levenshtein.LineOperations(source, target) {
+		fmt.Println(op)
+	}
+
+	// Output:
+	//   keep line 0: one
+	//   swap line 1: 2
+	//   keep line 2: three
+	// insert line 3: four
+}
+
+func ExampleNGramSimilarity() {
+	fmt.Println(levenshtein.NGramSimilarity("night", "night", 2))
+	fmt.Println(levenshtein.NGramSimilarity("night", "abcde", 2))
+	fmt.Println(levenshtein.NGramSimilarity("night", "nacht", 2))
+	fmt.Println(levenshtein.NGramSimilarity("a", "a", 3))
+
+	// Output:
+	// 1
+	// 0
+	// 0.25
+	// 1
+}
+
+func ExampleMatrix_AppendOperations() {
+	var ops []levenshtein.Operation
+	for _, target := range []string{"arose", "house"} {
+		matrix := levenshtein.Build("horse", target)
+		ops = matrix.AppendOperations(ops[:0])
+		fmt.Println(ops[len(ops)-1].Result, len(ops))
+	}
+
+	// Output:
+	// arose 6
+	// house 5
+}
+
+func ExampleTransliterate() {
+	fmt.Println(levenshtein.Distance("Москва", "Moskva"))
+	fmt.Println(levenshtein.Distance("Москва", "Moskva", levenshtein.Transliterate()))
+	fmt.Println(levenshtein.Distance("Хрущёв", "Khrushchev", levenshtein.Transliterate()))
+	fmt.Println(levenshtein.Distance("Толстой", "Tolstoy", levenshtein.Transliterate()))
+	fmt.Println(levenshtein.Distance("Достоевский", "Dostoyevsky", levenshtein.Transliterate()))
+	fmt.Println(levenshtein.Distance("Café", "Cafe", levenshtein.Transliterate()))
+
+	// Output:
+	// 6
+	// 0
+	// 0
+	// 0
+	// 2
+	// 0
+}
+
+func ExampleRollingComparator() {
+	r := levenshtein.NewRollingComparator(2)
+	for _, line := range []string{
+		"connected to 10.0.0.1",
+		"connected to 10.0.0.2",
+		"request failed",
+		"connected to 10.0.0.3",
+		"request failed",
+		"shutting down",
+	} {
+		dist, closest := r.Add(line)
+		fmt.Printf("%d %q\n", dist, closest)
+	}
+
+	// Output:
+	// -1 ""
+	// 1 "connected to 10.0.0.1"
+	// 17 "connected to 10.0.0.2"
+	// 1 "connected to 10.0.0.2"
+	// 0 "request failed"
+	// 13 "request failed"
+}
+
+func ExampleMatrix_Symmetric() {
+	for _, options := range [][]levenshtein.Option{
+		nil,
+		{levenshtein.SetInsertCost(2), levenshtein.SetRemoveCost(2)},
+		{levenshtein.SetInsertCost(2)},
+	} {
+		matrix := levenshtein.Build("horse", "arose!", options...)
+		reversed := levenshtein.Build("arose!", "horse", options...)
+		fmt.Println(matrix.Symmetric(), matrix.Distance(), reversed.Distance())
+	}
+
+	// Output:
+	// true 4 4
+	// true 5 5
+	// false 5 4
+}
+
+func ExamplePrefixDistance() {
+	fmt.Println(levenshtein.PrefixDistance("aplpe", "apple pie"))
+	fmt.Println(levenshtein.PrefixDistance("aplpe", "apricot"))
+	fmt.Println(levenshtein.PrefixDistance("app", "apple pie"))
+	fmt.Println(levenshtein.Distance("aplpe", "apple pie"))
+
+	// Output:
+	// 2
+	// 3
+	// 0
+	// 4
+}
+
+func ExampleMatrix_HTML() {
+	fmt.Println(levenshtein.Build("horse", "arose").HTML())
+	fmt.Println(levenshtein.Build("a<b", "a>b & c").HTML())
+
+	// Output:
+	// <del>ho</del><ins>a</ins>r<ins>o</ins>se
+	// a<del>&lt;</del><ins>&gt;</ins>b<ins> &amp; c</ins>
+}
+
+func ExampleOperation_Format() {
+	op := levenshtein.Operations("horse", "arose")[0]
+	fmt.Printf("%v\n", op)
+	fmt.Printf("%+v\n", op)
+	fmt.Printf("%s\n", op)
+	fmt.Printf("%c\n", op)
+	fmt.Printf("%+c\n", op)
+	fmt.Printf("%d\n", op)
+
+	// Output:
+	//   swap a at index 0: aorse
+	//   swap a at index 0: aorse
+	//   swap a at index 0: aorse
+	// a
+	// a at index 0: aorse
+	// %!d(levenshtein.Operation=  swap a at index 0: aorse)
+}
+
+func ExampleDedup() {
+	fmt.Println(levenshtein.Dedup([]string{"color", "colour", "flavor", "flavour", "colors"}, 1))
+
+	// Output:
+	// [color flavor]
+}
+
+func ExampleIgnoreCase() {
+	fmt.Println(levenshtein.Distance("Horse", "hORSE"))
+	fmt.Println(levenshtein.Distance("Horse", "hORSE", levenshtein.IgnoreCase()))
+	fmt.Println(levenshtein.Distance("ÉCOLE", "école", levenshtein.IgnoreCase()))
+
+	// Output:
+	// 5
+	// 0
+	// 0
+}
+
+func ExampleIgnoreCaseASCII() {
+	fmt.Println(levenshtein.Distance("Horse", "hORSE", levenshtein.IgnoreCaseASCII()))
+	fmt.Println(levenshtein.Distance("ÉCOLE", "école", levenshtein.IgnoreCaseASCII()))
+
+	// Output:
+	// 0
+	// 1
+}
+
+func ExampleMatrix_ChangedRunes() {
+	inserted, removed := levenshtein.Build("horse", "arose").ChangedRunes()
+	fmt.Printf("%q %q\n", inserted, removed)
+
+	inserted, removed = levenshtein.Build("banana", "bandana split").ChangedRunes()
+	fmt.Printf("%q %q\n", inserted, removed)
+
+	// Output:
+	// ['a' 'o'] ['h' 'o']
+	// ['d' ' ' 's' 'p' 'l' 'i' 't'] []
+}
+
+func ExampleFilterByRatio() {
+	candidates := []string{"apple", "apply", "ample", "maple", "applesauce", "apples"}
+	for _, match := range levenshtein.FilterByRatio("apple", candidates, 0.8) {
+		fmt.Printf("%s %d %.2f\n", match.Candidate, match.Distance, match.Ratio)
+	}
+
+	// Output:
+	// apple 0 1.00
+	// apples 1 0.83
+	// apply 1 0.80
+	// ample 1 0.80
+}
+
+func ExamplePhoneticCost() {
+	cost := levenshtein.PhoneticCost(nil)
+	fmt.Println(cost('b', 'p'))
+	fmt.Println(cost('b', 'z'))
+	fmt.Println(cost('b', 'a'))
+
+	options := []levenshtein.Option{
+		levenshtein.SetInsertCost(levenshtein.PhoneticMaxCost),
+		levenshtein.SetRemoveCost(levenshtein.PhoneticMaxCost),
+		levenshtein.SetSwapCostFunc(cost),
+	}
+	fmt.Println(levenshtein.Distance("bat", "pat", options...))
+	fmt.Println(levenshtein.Distance("bat", "zat", options...))
+
+	// Output:
+	// 1
+	// 2
+	// 4
+	// 1
+	// 2
+}
+
+func ExampleSuffixDistance() {
+	fmt.Println(levenshtein.SuffixDistance("txet", "plain.text"))
+	fmt.Println(levenshtein.SuffixDistance("text", "plain.text"))
+	fmt.Println(levenshtein.SuffixDistance(".txt", "plain.text"))
+	fmt.Println(levenshtein.PrefixDistance("txet", "plain.text"))
+
+	// Output:
+	// 2
+	// 0
+	// 1
+	// 4
+}
+
+func ExampleInvert() {
+	ops := levenshtein.Operations("horse", "arose")
+	for _, op := range levenshtein.Invert(ops) {
+		fmt.Printf("%s %c at index %d\n", op.Type, op.Char, op.Index)
+	}
+	source, _ := levenshtein.Apply("arose", levenshtein.Invert(ops))
+	fmt.Println(source)
+
+	// Output:
+	// keep e at index 4
+	// keep s at index 3
+	// remove o at index 2
+	// keep r at index 1
+	// insert o at index 1
+	// swap h at index 0
+	// horse
+}
+
+func ExampleSetOpTypeNames() {
+	levenshtein.SetOpTypeNames(map[levenshtein.OpType]string{
+		levenshtein.Insert: "insérer",
+		levenshtein.Remove: "supprimer",
+		levenshtein.Swap:   "remplacer",
+	})
+	defer levenshtein.SetOpTypeNames(nil)
+
+	for _, op := range levenshtein.Operations("horse", "arose") {
+		fmt.Println(op)
+	}
+
+	// Output:
+	// remplacer a at index 0: aorse
+	// supprimer o at index 1: arse
+	//   keep r at index 1: arse
+	// insérer o at index 2: arose
+	//   keep s at index 3: arose
+	//   keep e at index 4: arose
+}
+
+func ExampleMatrix_OpCodes() {
+	// Equivalent to difflib.SequenceMatcher(None, "qabxcd", "abycdf").get_opcodes()
+	for _, opCode := range levenshtein.Build("qabxcd", "abycdf").OpCodes() {
+		fmt.Println(opCode)
+	}
+
+	// Output:
+	//  delete source[0:1] target[0:0]
+	//   equal source[1:3] target[0:2]
+	// replace source[3:4] target[2:3]
+	//   equal source[4:6] target[3:5]
+	//  insert source[6:6] target[5:6]
+}
+
+func ExampleLoose() {
+	fmt.Println(levenshtein.Distance("Café", "cafe", levenshtein.Loose()))
+	fmt.Println(levenshtein.Distance("CAFÉ", "café", levenshtein.Loose()))
+	fmt.Println(levenshtein.Distance("Ångström", "angstrom", levenshtein.Loose()))
+	fmt.Println(levenshtein.Distance("Straße", "STRASSE", levenshtein.Loose()))
+	fmt.Println(levenshtein.Distance("Café", "cafe", levenshtein.IgnoreCase()))
+
+	// Output:
+	// 0
+	// 0
+	// 0
+	// 0
+	// 1
+}
+
+func ExampleSlugNormalize() {
+	fmt.Println(levenshtein.Distance("Hello, World!", "hello-world", levenshtein.SlugNormalize()))
+	fmt.Println(levenshtein.Distance("Crème Brûlée", "creme-brulee", levenshtein.SlugNormalize()))
+	fmt.Println(levenshtein.Distance("/blog/my-post/", "blog my post", levenshtein.SlugNormalize()))
+	fmt.Println(levenshtein.Distance("Top 10 Tips", "top-ten-tips", levenshtein.SlugNormalize()))
+
+	// Output:
+	// 0
+	// 0
+	// 0
+	// 3
+}
+
+func ExampleFirstCharWeight() {
+	weight := levenshtein.FirstCharWeight(3)
+	fmt.Println(levenshtein.Distance("karen", "maren", weight))
+	fmt.Println(levenshtein.Distance("karan", "karen", weight))
+	fmt.Println(levenshtein.Distance("aren", "karen", weight))
+	fmt.Println(levenshtein.Distance("karen", "maren"))
+
+	// Output:
+	// 3
+	// 1
+	// 3
+	// 1
+}
+
+func ExampleStreamTopK() {
+	candidates := make(chan string)
+	go func() {
+		defer close(candidates)
+		for _, word := range []string{"kitchen", "sitting", "mitten", "knitting", "bitten", "smitten"} {
+			candidates <- word
+		}
+	}()
+
+	for _, match := range levenshtein.StreamTopK("kitten", candidates, 3) {
+		fmt.Println(match.Candidate, match.Index, match.Distance)
+	}
+
+	// Output:
+	// mitten 2 1
+	// bitten 4 1
+	// kitchen 0 2
+}
+
+func ExampleWithinDistance() {
+	candidates := []string{"kitchen", "sitting", "mitten", "knitting", "bitten", "smitten"}
+	for _, match := range levenshtein.WithinDistance("kitten", candidates, 2) {
+		fmt.Println(match.Candidate, match.Index, match.Distance)
+	}
+
+	// Output:
+	// mitten 2 1
+	// bitten 4 1
+	// kitchen 0 2
+	// smitten 5 2
+}
+
+func ExampleMatrix_Explain() {
+	fmt.Println(levenshtein.Build("horse", "arose").Explain())
+
+	// Output:
+	// 3 edits: 1 substitution (h→a), 1 deletion (o), 1 insertion (o)
+}
+
+func ExampleMatrix_Replacements() {
+	for _, r := range levenshtein.Build("the quick brown fox", "the slow brown cat").Replacements() {
+		fmt.Println(r)
+	}
+
+	// Output:
+	// replace "quick" with "slow" at source[4:9] target[4:8]
+	// replace "fox" with "cat" at source[16:19] target[15:18]
+}
+
+func ExampleMaxRemoves() {
+	fmt.Println(levenshtein.Distance("abcd", "bcde"))
+	fmt.Println(levenshtein.Distance("abcd", "bcde", levenshtein.MaxRemoves(0)))
+	fmt.Println(levenshtein.Distance("abcd", "bcd", levenshtein.MaxRemoves(0)))
+
+	// Output:
+	// 2
+	// 4
+	// -1
+}
+
+func ExampleNumericDistance() {
+	fmt.Println(levenshtein.NumericDistance("007", "7"))
+	fmt.Println(levenshtein.NumericDistance("1,234", "1234"))
+	fmt.Println(levenshtein.NumericDistance("1,234", "1,243"))
+
+	// Output:
+	// 0
+	// 0
+	// 2
+}
+
+func ExampleMatrix_PathString() {
+	fmt.Println(levenshtein.Build("horse", "arose").PathString())
+
+	// Output:
+	//       a   r   o   s   e
+	//     [0]  1   2   3   4   5
+	//  h   1  [1]  2   3   4   5
+	//  o   2  [2]  2   2   3   4
+	//  r   3   3  [2] [3]  3   4
+	//  s   4   4   3   3  [3]  4
+	//  e   5   5   4   4   4  [3]
+}
+
+func ExampleMatrix_CellOrigin() {
+	m := levenshtein.Build("horse", "arose", levenshtein.WithTrace())
+	fmt.Println(m.CellOrigin(1, 1))
+	fmt.Println(m.CellOrigin(2, 2))
+	fmt.Println(m.CellOrigin(3, 3))
+	fmt.Println(m.CellOrigin(5, 5))
+
+	// Output:
+	// [swap]
+	// [swap]
+	// [insert remove swap]
+	// [keep]
+}
+
+func ExampleBuildConfig() {
+	cfg := levenshtein.Config{
+		SwapCost:   2,
+		IgnoreCase: true,
+	}
+	fmt.Println(levenshtein.BuildConfig("Kitten", "SITTING", cfg).Distance())
+
+	// Output: 5
+}
+
+func ExampleSimilarityPercent() {
+	fmt.Println(levenshtein.SimilarityPercent("kitten", "sitting"))
+	fmt.Println(levenshtein.SimilarityPercent("abcdefgh", "abcdefgx"))
+	fmt.Println(levenshtein.SimilarityPercent("", ""))
+
+	// Output:
+	// 57
+	// 88
+	// 100
+}
+
+func ExampleCompilePattern() {
+	p, err := levenshtein.CompilePattern("a[xy]c?")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(p.Distance("axcz"))
+	fmt.Println(p.Distance("aycq"))
+	fmt.Println(p.Distance("abcz"))
+	fmt.Println(p.Distance("axc"))
+
+	// Output:
+	// 0
+	// 0
+	// 1
+	// 1
+}
+
+func ExampleMatrix_OperationsTargetOrder() {
+	m := levenshtein.Build("horse", "arose", levenshtein.SetSwapCost(2))
+	for _, op := range m.Operations() {
+		fmt.Println(op)
+	}
+	fmt.Println()
+	for _, op := range m.OperationsTargetOrder() {
+		fmt.Println(op)
+	}
+
+	// Output:
+	// remove h at index 0: orse
+	// remove o at index 0: rse
+	// insert a at index 0: arse
+	//   keep r at index 1: arse
+	// insert o at index 2: arose
+	//   keep s at index 3: arose
+	//   keep e at index 4: arose
+	//
+	// insert a at index 0: ahorse
+	// remove h at index 1: aorse
+	// remove o at index 1: arse
+	//   keep r at index 1: arse
+	// insert o at index 2: arose
+	//   keep s at index 3: arose
+	//   keep e at index 4: arose
+}
+
+func ExampleCaseChangeCost() {
+	swapCost := levenshtein.SetSwapCost(4)
+	caseCost := levenshtein.CaseChangeCost(1)
+	fmt.Println(levenshtein.Distance("Horse", "horse", swapCost))
+	fmt.Println(levenshtein.Distance("Horse", "horse", swapCost, caseCost))
+	fmt.Println(levenshtein.Distance("HORSE", "horse", swapCost, caseCost))
+	fmt.Println(levenshtein.Distance("Horse", "korse", swapCost, caseCost))
+
+	// Output:
+	// 2
+	// 1
+	// 5
+	// 2
+}
+
+func ExampleAdjacentDistances() {
+	versions := []string{"color", "colour", "colours", "colours", "flavours"}
+	fmt.Println(levenshtein.AdjacentDistances(versions))
+
+	// Output: [1 1 0 4]
+}
+
+func ExampleMatrix_UniquePath() {
+	fmt.Println(levenshtein.Build("kitten", "sitten").UniquePath())
+	fmt.Println(levenshtein.Build("ab", "ba").UniquePath())
+
+	// Output:
+	// true
+	// false
+}
+
+func ExampleBuildTokens() {
+	source := []string{"The", "quick", "brown", "fox"}
+	target := []string{"the", "QUICK", "red", "fox"}
+	m := levenshtein.BuildTokens(source, target, strings.EqualFold)
+	for _, op := range m.Operations() {
+		fmt.Println(op.Type, op.Token)
+	}
+
+	// Output:
+	// keep the
+	// keep QUICK
+	// swap red
+	// keep fox
+}
+
+func ExampleDistanceWithinMatrix() {
+	m, dist, ok := levenshtein.DistanceWithinMatrix("kitten", "sitting", 1)
+	fmt.Println(dist, ok)
+
+	// The calculation gave up after the row for "kitte"
+	fmt.Println(m.At(5, 5), m.At(5, 7))
+	fmt.Println(m.At(6, 7))
+
+	// Output:
+	// 2 false
+	// 2 4
+	// 0
+}
+
+func ExampleCacheKey() {
+	fmt.Println(levenshtein.CacheKey("kitten", "sitting"))
+	fmt.Println(levenshtein.CacheKey("sitting", "kitten"))
+	fmt.Println(levenshtein.CacheKey("Kitten", "Sitting", levenshtein.IgnoreCase()))
+	fmt.Println(levenshtein.CacheKey("sitting", "kitten", levenshtein.SetInsertCost(2)))
+
+	// Output:
+	// i1 r1 s1 f1 "kitten" "sitting"
+	// i1 r1 s1 f1 "kitten" "sitting"
+	// i1 r1 s1 f1 "kitten" "sitting"
+	// i2 r1 s1 f1 "sitting" "kitten"
+}
+
+func ExampleConfusionPairs() {
+	ocr := levenshtein.ConfusionPairs(map[string]string{"rn": "m", "l": "1"}, 0)
+	fmt.Println(levenshtein.Distance("rnodern", "modern"))
+	fmt.Println(levenshtein.Distance("rnodern", "modern", ocr))
+	fmt.Println(levenshtein.Distance("he11o wor1d", "hello world", ocr))
+	for _, op := range levenshtein.Operations("rnodern", "modern", ocr) {
+		fmt.Println(op)
+	}
+
+	// Output:
+	// 2
+	// 0
+	// 0
+	//   swap m at index 0: mnodern
+	// remove n at index 1: modern
+	//   keep o at index 1: modern
+	//   keep d at index 2: modern
+	//   keep e at index 3: modern
+	//   keep r at index 4: modern
+	//   keep n at index 5: modern
+}
+
+func ExampleMedianString() {
+	cluster := []string{"color", "colour", "collor", "colr", "kolor"}
+	fmt.Println(levenshtein.MedianString(cluster))
+
+	// Output: color 4
+}
+
+func ExampleCircularDistance() {
+	fmt.Println(levenshtein.Distance("abcde", "cdeab"))
+	fmt.Println(levenshtein.CircularDistance("abcde", "cdeab"))
+	fmt.Println(levenshtein.CircularDistance("abcde", "cdxab"))
+
+	// Output:
+	// 4
+	// 0
+	// 1
+}
+
+func ExampleSetPositionWeights() {
+	weights := levenshtein.SetPositionWeights([]float64{10})
+	fmt.Println(levenshtein.Distance("xbc", "abc"))
+	fmt.Println(levenshtein.Distance("xbc", "abc", weights))
+	for _, op := range levenshtein.Operations("aab", "ab", weights) {
+		fmt.Println(op)
+	}
+
+	// Output:
+	// 1
+	// 10
+	//   keep a at index 0: aab
+	// remove a at index 1: ab
+	//   keep b at index 1: ab
+}
+
+func ExampleMatrix_NetLengthChange() {
+	fmt.Println(levenshtein.Build("color", "colours").NetLengthChange())
+	fmt.Println(levenshtein.Build("saturday", "sunday").NetLengthChange())
+	fmt.Println(levenshtein.Build("kitten", "mitten").NetLengthChange())
+
+	// Output:
+	// 2
+	// -2
+	// 0
+}
+
+func ExamplePreferOperation() {
+	swapCost := levenshtein.SetSwapCost(2)
+	for _, op := range levenshtein.Operations("ab", "cb", swapCost) {
+		fmt.Println(op)
+	}
+	for _, op := range levenshtein.Operations("ab", "cb", swapCost, levenshtein.PreferOperation(levenshtein.Swap)) {
+		fmt.Println(op)
+	}
+
+	// Output:
+	// remove a at index 0: b
+	// insert c at index 0: cb
+	//   keep b at index 1: cb
+	//   swap c at index 0: cb
+	//   keep b at index 1: cb
+}
+
+func ExampleIgnoreSubstrings() {
+	noise := levenshtein.IgnoreSubstrings(" (New)", " Ltd.", " Inc.")
+	fmt.Println(levenshtein.Distance("Acme Widget (New)", "Acme Widget", noise))
+	fmt.Println(levenshtein.Distance("Acme Ltd. Widget", "Acme Inc. Widgets", noise))
+
+	// Output:
+	// 0
+	// 1
+}
+
+func ExampleMatrix_AlignedPairs() {
+	var source, target []rune
+	for _, pair := range levenshtein.Build("horse", "arose").AlignedPairs() {
+		if pair.Source == levenshtein.Gap {
+			pair.Source = '-'
+		}
+		if pair.Target == levenshtein.Gap {
+			pair.Target = '-'
+		}
+		source, target = append(source, pair.Source), append(target, pair.Target)
+	}
+	fmt.Println(string(source))
+	fmt.Println(string(target))
+
+	// Output:
+	// hor-se
+	// a-rose
+}
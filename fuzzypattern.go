@@ -0,0 +1,110 @@
+package levenshtein
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// FuzzyPattern is a compiled pattern which can contain wildcards and
+// character classes, for typo-tolerant matching against templates. It is safe
+// for concurrent use by multiple goroutines.
+type FuzzyPattern struct {
+	elems []patternElem
+}
+
+// patternElem is a single element of a fuzzy pattern, which matches one
+// character of the target string.
+type patternElem struct {
+	any   bool   // Whether the element is a ? wildcard
+	chars []rune // The characters matched by a literal or class
+}
+
+// matches reports whether the element matches the given character.
+func (e patternElem) matches(r rune) bool {
+	if e.any {
+		return true
+	}
+	for _, c := range e.chars {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}
+
+// CompilePattern compiles a fuzzy pattern. In addition to literal characters,
+// the pattern may contain the wildcard ?, which matches any single character,
+// and character classes such as [abc], which match any one of the characters
+// between the brackets. A backslash escapes the following character, so that
+// e.g. \? matches a literal question mark. An error is returned if the
+// pattern contains an unterminated or empty character class, or ends in a
+// backslash.
+func CompilePattern(pattern string) (*FuzzyPattern, error) {
+	p := &FuzzyPattern{}
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '?':
+			p.elems = append(p.elems, patternElem{any: true})
+		case '[':
+			start := i
+			var chars []rune
+			for i++; i < len(runes) && runes[i] != ']'; i++ {
+				if runes[i] == '\\' {
+					i++
+					if i == len(runes) {
+						break
+					}
+				}
+				chars = append(chars, runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("levenshtein: unterminated character class at index %d", start)
+			}
+			if len(chars) == 0 {
+				return nil, fmt.Errorf("levenshtein: empty character class at index %d", start)
+			}
+			p.elems = append(p.elems, patternElem{chars: chars})
+		case '\\':
+			i++
+			if i == len(runes) {
+				return nil, fmt.Errorf("levenshtein: trailing backslash in pattern")
+			}
+			p.elems = append(p.elems, patternElem{chars: []rune{runes[i]}})
+		default:
+			p.elems = append(p.elems, patternElem{chars: []rune{r}})
+		}
+	}
+	return p, nil
+}
+
+// Distance returns the edit distance between the pattern and the target
+// string, using the default costs. Each element of the pattern counts as a
+// single character, and swapping an element for a character which it matches
+// is free - so e.g. the pattern "a[xy]c?" is at distance 0 from "aycz", and at
+// distance 1 from "abcz".
+func (p *FuzzyPattern) Distance(target string) int {
+	prev := make([]int, utf8.RuneCountInString(target)+1)
+	row := make([]int, len(prev))
+	for j := range prev {
+		prev[j] = j * DefaultInsertCost
+	}
+	for _, elem := range p.elems {
+		row[0] = prev[0] + DefaultRemoveCost
+		j := 1
+		for _, r := range target {
+			swap := DefaultSwapCost
+			if elem.matches(r) {
+				swap = 0
+			}
+			row[j] = min(
+				row[j-1]+DefaultInsertCost,
+				prev[j]+DefaultRemoveCost,
+				prev[j-1]+swap,
+			)
+			j++
+		}
+		prev, row = row, prev
+	}
+	return prev[len(prev)-1]
+}
@@ -1 +1,5 @@
 module github.com/nathanjcochran/levenshtein
+
+go 1.19
+
+require golang.org/x/text v0.14.0
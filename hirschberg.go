@@ -0,0 +1,204 @@
+package levenshtein
+
+// OperationsCallback calls cb for each of the operations in a minimal list of
+// edit operations required to transform the source string into the target
+// string, in order, stopping early if cb returns false. Unlike Operations, it
+// never builds the full edit matrix or the list of operations: the
+// operations are found using Hirschberg's algorithm, which only needs space
+// proportional to the length of the target string, at the cost of roughly
+// doubling the time taken. This makes it suitable for very large inputs. The
+// operations have the same form as those returned by Operations, but if there
+// are several equally good lists of operations, it may find a different one.
+//
+// Matrices built with SetAffineGap, ConfusionPairs, or limits on the number
+// of operations (see MaxInserts) need the full matrix, so for those options,
+// the matrix is built as usual, and its operations are passed to cb one at a
+// time.
+func OperationsCallback(source, target string, cb func(Operation) bool, options ...Option) {
+	m := newConfig(options)
+	m.source = m.prepare(source)
+	m.target = m.prepare(target)
+	h := &hirschberg{
+		m:     m,
+		visit: cb,
+		fwd:   make([]int, len(m.target)+1),
+		bwd:   make([]int, len(m.target)+1),
+	}
+	if m.resultMode == ResultFull {
+		h.result = append([]rune(nil), m.source...)
+		h.resultStr = string(m.source)
+	}
+
+	if m.affine || m.confusions != nil || m.limited() {
+		m.allocate()
+		m.fill()
+		m.walk(len(m.source), len(m.target), func(op Operation) bool {
+			h.cost = op.CumulativeCost
+			return h.emit(op)
+		})
+		return
+	}
+	h.align(0, len(m.source), 0, len(m.target))
+}
+
+// hirschberg holds the state of an alignment using Hirschberg's algorithm.
+type hirschberg struct {
+	m         *Matrix
+	visit     func(Operation) bool
+	cost      int    // Cumulative cost of the operations visited so far
+	i, j      int    // Positions in the source and target after the operations
+	fwd, bwd  []int  // Workspaces for the forward and backward rows
+	result    []rune // Intermediate result, for ResultFull
+	resultStr string
+}
+
+// align visits the operations which transform source[i0:i1] into
+// target[j0:j1], returning false if the visitor stopped early.
+func (h *hirschberg) align(i0, i1, j0, j1 int) bool {
+	if i1-i0 <= 1 || j1-j0 <= 1 {
+		return h.alignSmall(i0, i1, j0, j1)
+	}
+
+	// Find the column at which an optimal path crosses the middle row, by
+	// combining the costs of reaching each cell of the middle row from the
+	// start with the costs of reaching the end from each of them
+	mid := (i0 + i1) / 2
+	fwd, bwd := h.fwd[:j1-j0+1], h.bwd[:j1-j0+1]
+	h.forward(i0, mid, j0, fwd)
+	h.backward(mid, i1, j0, j1, bwd)
+	split := 0
+	for k := range fwd {
+		if fwd[k]+bwd[k] < fwd[split]+bwd[split] {
+			split = k
+		}
+	}
+	return h.align(i0, mid, j0, j0+split) && h.align(mid, i1, j0+split, j1)
+}
+
+// forward fills row with the costs of transforming source[i0:i1] into each
+// prefix target[j0:j0+k] of the target range.
+func (h *hirschberg) forward(i0, i1, j0 int, row []int) {
+	m := h.m
+	row[0] = 0
+	for k := 1; k < len(row); k++ {
+		row[k] = row[k-1] + m.insert(j0+k)
+	}
+	for i := i0 + 1; i <= i1; i++ {
+		diag := row[0]
+		row[0] += m.remove(i)
+		for k := 1; k < len(row); k++ {
+			above := row[k]
+			row[k] = min(above+m.remove(i), row[k-1]+m.insert(j0+k), diag+m.swap(i, j0+k))
+			diag = above
+		}
+	}
+}
+
+// backward fills row with the costs of transforming source[i0:i1] into each
+// suffix target[j0+k:j1] of the target range.
+func (h *hirschberg) backward(i0, i1, j0, j1 int, row []int) {
+	m := h.m
+	last := j1 - j0
+	row[last] = 0
+	for k := last - 1; k >= 0; k-- {
+		row[k] = row[k+1] + m.insert(j0+k+1)
+	}
+	for i := i1 - 1; i >= i0; i-- {
+		diag := row[last]
+		row[last] += m.remove(i + 1)
+		for k := last - 1; k >= 0; k-- {
+			below := row[k]
+			row[k] = min(below+m.remove(i+1), row[k+1]+m.insert(j0+k+1), diag+m.swap(i+1, j0+k+1))
+			diag = below
+		}
+	}
+}
+
+// alignSmall visits the operations which transform source[i0:i1] into
+// target[j0:j1] when one of the ranges has at most one character, so that a
+// full matrix for the ranges only needs space proportional to the other.
+func (h *hirschberg) alignSmall(i0, i1, j0, j1 int) bool {
+	m := h.m
+
+	// Fill a matrix covering only the ranges, with costs relative to the
+	// start of the ranges. Cell (i, j) of the full matrix is stored at
+	// cells[i-i0][j-j0], so the costs of the operations are still those of
+	// their positions in the full strings.
+	cells := make([][]int, i1-i0+1)
+	for k := range cells {
+		cells[k] = make([]int, j1-j0+1)
+	}
+	at := func(i, j int) int { return cells[i-i0][j-j0] }
+	for j := j0 + 1; j <= j1; j++ {
+		cells[0][j-j0] = at(i0, j-1) + m.insert(j)
+	}
+	for i := i0 + 1; i <= i1; i++ {
+		cells[i-i0][0] = at(i-1, j0) + m.remove(i)
+		for j := j0 + 1; j <= j1; j++ {
+			cells[i-i0][j-j0] = min(
+				at(i-1, j)+m.remove(i),
+				at(i, j-1)+m.insert(j),
+				at(i-1, j-1)+m.swap(i, j),
+			)
+		}
+	}
+
+	// Trace back from the end of the ranges, and then visit the operations
+	// in order
+	var ops []Operation
+	for i, j := i1, j1; i > i0 || j > j0; {
+		for _, opType := range m.opOrder() {
+			var pi, pj int
+			switch {
+			case opType == Insert && j > j0 && at(i, j-1)+m.insert(j) == at(i, j):
+				pi, pj = i, j-1
+			case opType == Remove && i > i0 && at(i-1, j)+m.remove(i) == at(i, j):
+				pi, pj = i-1, j
+			case opType == Swap && i > i0 && j > j0 && m.source[i-1] != m.target[j-1] && at(i-1, j-1)+m.swap(i, j) == at(i, j),
+				opType == Keep && i > i0 && j > j0 && m.source[i-1] == m.target[j-1] && at(i-1, j-1)+m.swap(i, j) == at(i, j):
+				pi, pj = i-1, j-1
+			default:
+				continue
+			}
+			ops = append(ops, m.operation(opType, i, j, at(i, j)))
+			i, j = pi, pj
+			break
+		}
+	}
+	base := h.cost
+	for k := len(ops) - 1; k >= 0; k-- {
+		op := ops[k]
+		h.cost = base + op.CumulativeCost
+		if !h.emit(op) {
+			return false
+		}
+	}
+	return true
+}
+
+// emit fills in the cumulative cost and result of an operation, and visits it.
+func (h *hirschberg) emit(op Operation) bool {
+	m := h.m
+	op.CumulativeCost = h.cost
+	switch op.Type {
+	case Insert:
+		h.j++
+	case Remove:
+		h.i++
+	default:
+		h.i, h.j = h.i+1, h.j+1
+	}
+	switch m.resultMode {
+	case ResultFull:
+		if op.Type != Keep {
+			h.result, _ = applyOp(h.result, op)
+			h.resultStr = string(h.result)
+		}
+		op.Result = h.resultStr
+	case ResultFinalOnly:
+		if h.i == len(m.source) && h.j == len(m.target) {
+			op.Result = string(m.target)
+		}
+	}
+	return h.visit(op)
+}
@@ -0,0 +1,118 @@
+package levenshtein_test
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func TestOperationsCallback(t *testing.T) {
+	long := strings.Repeat("the quick brown fox ", 20)
+	for _, test := range []struct {
+		source, target string
+		options        []levenshtein.Option
+	}{
+		{"", "", nil},
+		{"", "abc", nil},
+		{"abc", "", nil},
+		{"a", "b", nil},
+		{"kitten", "sitting", nil},
+		{"horse", "arose", nil},
+		{"abcdef", "azced", nil},
+		{"intention", "execution", nil},
+		{long, strings.ReplaceAll(long, "o", "0"), nil},
+		{long, strings.ReplaceAll(long, "quick ", ""), nil},
+		{"kitten", "sitting", []levenshtein.Option{levenshtein.SetSwapCost(3)}},
+		{"kitten", "sitting", []levenshtein.Option{levenshtein.SetInsertCost(2), levenshtein.SetRemoveCost(5)}},
+		{"kitten", "sitting", []levenshtein.Option{levenshtein.PreferOperation(levenshtein.Insert)}},
+		{"Kitten", "sitting", []levenshtein.Option{levenshtein.IgnoreCase()}},
+		{"kitten", "sitting", []levenshtein.Option{levenshtein.SetResultMode(levenshtein.ResultFinalOnly)}},
+		{"kitten", "sitting", []levenshtein.Option{levenshtein.SetResultMode(levenshtein.ResultNone)}},
+		// Options which need the full matrix
+		{"abcdef", "abef", []levenshtein.Option{levenshtein.SetAffineGap(3, 1)}},
+		{"kitten", "sitting", []levenshtein.Option{levenshtein.MaxSwaps(1)}},
+	} {
+		matrix := levenshtein.Build(test.source, test.target, test.options...)
+		want := matrix.Operations()
+
+		got := []levenshtein.Operation{}
+		levenshtein.OperationsCallback(test.source, test.target, func(op levenshtein.Operation) bool {
+			got = append(got, op)
+			return true
+		}, test.options...)
+
+		if matrix.UniquePath() && !reflect.DeepEqual(got, want) {
+			t.Errorf("%q, %q: OperationsCallback() = %v, want %v", test.source, test.target, got, want)
+		}
+		if len(got) != len(want) && len(want) == 0 {
+			t.Errorf("%q, %q: OperationsCallback() visited %d operations, want none", test.source, test.target, len(got))
+		}
+		if len(got) == 0 {
+			continue
+		}
+		if cost := got[len(got)-1].CumulativeCost; cost != matrix.Distance() {
+			t.Errorf("%q, %q: final CumulativeCost = %d, want %d", test.source, test.target, cost, matrix.Distance())
+		}
+		if len(got) != len(want) {
+			continue // There are several minimal lists, of different lengths
+		}
+		if result, err := levenshtein.Apply(test.source, got); err != nil || result != strings.ToLower(test.target) && result != test.target {
+			t.Errorf("%q, %q: Apply(OperationsCallback()) = %q, %v", test.source, test.target, result, err)
+		}
+		if last, wantLast := got[len(got)-1].Result, want[len(want)-1].Result; last != wantLast {
+			t.Errorf("%q, %q: final Result = %q, want %q", test.source, test.target, last, wantLast)
+		}
+		if want[0].Result == "" {
+			continue // Not every operation has a result to verify
+		}
+		if err := levenshtein.VerifyResults(got); err != nil {
+			t.Errorf("%q, %q: VerifyResults(OperationsCallback()): %v", test.source, test.target, err)
+		}
+	}
+}
+
+func TestOperationsCallbackStop(t *testing.T) {
+	for _, options := range [][]levenshtein.Option{
+		nil,
+		{levenshtein.SetAffineGap(3, 1)},
+	} {
+		want := levenshtein.Build("kitten", "sitting", options...).Operations()[:3]
+		var got []levenshtein.Operation
+		levenshtein.OperationsCallback("kitten", "sitting", func(op levenshtein.Operation) bool {
+			got = append(got, op)
+			return len(got) < 3
+		}, options...)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("OperationsCallback() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestOperationsCallbackAllocations(t *testing.T) {
+	// The space used is proportional to the length of the strings, rather
+	// than to the product of their lengths
+	const n = 2000
+	source, target := strings.Repeat("ab", n/2), strings.Repeat("ba", n/2)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	levenshtein.OperationsCallback(source, target, func(levenshtein.Operation) bool {
+		return true
+	}, levenshtein.SetResultMode(levenshtein.ResultNone))
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1000*n {
+		t.Errorf("OperationsCallback() allocated %d bytes for strings of length %d, want at most %d", allocated, n, 1000*n)
+	}
+}
+
+func BenchmarkOperationsCallback(b *testing.B) {
+	source, target := strings.Repeat("ab", 1000), strings.Repeat("ba", 1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		levenshtein.OperationsCallback(source, target, func(levenshtein.Operation) bool {
+			return true
+		}, levenshtein.SetResultMode(levenshtein.ResultNone))
+	}
+}
@@ -0,0 +1,45 @@
+package levenshtein
+
+import (
+	"html"
+	"strings"
+)
+
+// HTML returns an HTML rendering of the alignment between the source and
+// target strings, in which removed characters are wrapped in <del> elements,
+// inserted characters are wrapped in <ins> elements, and kept characters are
+// left as plain text. A swap is rendered as the removal of the source
+// character followed by the insertion of the target character, and
+// consecutive removals and insertions are grouped together. All characters
+// are HTML-escaped, so the result is safe to embed directly in a web page.
+func (m *Matrix) HTML() string {
+	var b, del, ins strings.Builder
+	flush := func() {
+		if del.Len() > 0 {
+			b.WriteString("<del>" + del.String() + "</del>")
+			del.Reset()
+		}
+		if ins.Len() > 0 {
+			b.WriteString("<ins>" + ins.String() + "</ins>")
+			ins.Reset()
+		}
+	}
+
+	m.walk(len(m.source), len(m.target), func(op Operation) bool {
+		switch op.Type {
+		case Insert:
+			ins.WriteString(html.EscapeString(string(op.Char)))
+		case Remove:
+			del.WriteString(html.EscapeString(string(op.Char)))
+		case Swap:
+			del.WriteString(html.EscapeString(string(op.FromChar)))
+			ins.WriteString(html.EscapeString(string(op.Char)))
+		case Keep:
+			flush()
+			b.WriteString(html.EscapeString(string(op.Char)))
+		}
+		return true
+	})
+	flush()
+	return b.String()
+}
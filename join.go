@@ -0,0 +1,101 @@
+package levenshtein
+
+import "sort"
+
+// JoinPair is a pair of strings matched by a fuzzy join: the index of a string
+// of the left list, the index of the string of the right list which it was
+// matched with, and the edit distance between them.
+type JoinPair struct {
+	LeftIdx  int
+	RightIdx int
+	Distance int
+}
+
+// FuzzyJoin matches each string of the left list with the closest string of
+// the right list within edit distance maxDist (the first one, if several are
+// equally close) - e.g. for linking records by name. Left strings with no
+// right string within maxDist are left unmatched, and have no pair in the
+// result, which is in the order of the left list. A right string may be
+// matched with any number of left strings: see FuzzyJoinUnique for a
+// one-to-one join. Right strings whose difference in length from the left
+// string alone rules them out are skipped without being compared, and once a
+// candidate match has been found, each remaining comparison stops as soon as
+// it is clear that it cannot improve on it.
+func FuzzyJoin(left, right []string, maxDist int, options ...Option) []JoinPair {
+	pairs := []JoinPair{}
+	if maxDist < 0 {
+		return pairs
+	}
+	targets := prepareAll(right, options)
+	for i, source := range left {
+		p := Compile(source, options...)
+		best := JoinPair{LeftIdx: i, RightIdx: -1}
+		for max, j := maxDist, 0; j < len(targets) && max >= 0; j++ {
+			if p.config.lengthCost(len(p.config.source), len(targets[j])) > max {
+				continue
+			}
+			if dist, ok := p.distance(targets[j], max); ok {
+				best.RightIdx, best.Distance = j, dist
+				max = dist - 1 // Only a strictly closer match can improve on it
+			}
+		}
+		if best.RightIdx >= 0 {
+			pairs = append(pairs, best)
+		}
+	}
+	return pairs
+}
+
+// FuzzyJoinUnique is a one-to-one variant of FuzzyJoin, in which each string
+// of either list is matched with at most one string of the other. The pairs
+// within maxDist are assigned greedily, in order of ascending distance (with
+// ties going to the earliest left string, and then the earliest right
+// string), so each pair is the closest match available once the closer pairs
+// have been assigned. This does not necessarily minimize the total distance of
+// the pairs. The result is in the order of the left list.
+func FuzzyJoinUnique(left, right []string, maxDist int, options ...Option) []JoinPair {
+	candidates := []JoinPair{}
+	if maxDist < 0 {
+		return candidates
+	}
+	targets := prepareAll(right, options)
+	for i, source := range left {
+		p := Compile(source, options...)
+		for j, target := range targets {
+			if p.config.lengthCost(len(p.config.source), len(target)) > maxDist {
+				continue
+			}
+			if dist, ok := p.distance(target, maxDist); ok {
+				candidates = append(candidates, JoinPair{LeftIdx: i, RightIdx: j, Distance: dist})
+			}
+		}
+	}
+
+	// The candidates are already ordered by left index, and then right index
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Distance < candidates[j].Distance
+	})
+	pairs := []JoinPair{}
+	usedLeft, usedRight := make([]bool, len(left)), make([]bool, len(right))
+	for _, c := range candidates {
+		if !usedLeft[c.LeftIdx] && !usedRight[c.RightIdx] {
+			usedLeft[c.LeftIdx], usedRight[c.RightIdx] = true, true
+			pairs = append(pairs, c)
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].LeftIdx < pairs[j].LeftIdx
+	})
+	return pairs
+}
+
+// prepareAll prepares each of the strings using the given options, so that
+// they can be compared against many patterns without being prepared again.
+func prepareAll(strs []string, options []Option) [][]rune {
+	m := newConfig(options)
+	prepared := make([][]rune, len(strs))
+	for i, s := range strs {
+		prepared[i] = m.prepare(s)
+	}
+	return prepared
+}
@@ -0,0 +1,53 @@
+package levenshtein
+
+// KeystrokeDistance returns the minimum number of keystrokes needed to type
+// the changes which turn the source string into the target string in a text
+// editor, counting the cursor movements needed to reach each edit as well as
+// the edits themselves. This is a more realistic measure of typing effort
+// than the edit distance, since an edit at the end of a long string costs
+// more to reach than one at the start. The model is:
+//
+//   - The cursor starts before the first character of the source string, and
+//     the edits are made from left to right.
+//   - Typing a character inserts it before the cursor, which costs one
+//     keystroke.
+//   - Pressing Delete removes the character after the cursor, which costs one
+//     keystroke.
+//   - Pressing the right arrow key moves the cursor past one character, which
+//     costs one keystroke.
+//   - Replacing a character means deleting it and typing its replacement,
+//     which costs two keystrokes.
+//   - Once the last edit has been made, the cursor does not need to move past
+//     the rest of the string, so the unchanged suffix costs nothing.
+//
+// Keystrokes are counted per character (in runes), and no other keys (e.g.
+// Home, End, or selection) are modeled.
+func KeystrokeDistance(a, b string) int {
+	m := Build(a, b, UseCostModel(keystrokeCosts{}))
+
+	// Any common suffix can be left untouched, so the best keystroke count
+	// is that of the cheapest prefixes which leave one
+	i, j := len(m.source), len(m.target)
+	best := m.at(i, j)
+	for i > 0 && j > 0 && m.source[i-1] == m.target[j-1] {
+		i, j = i-1, j-1
+		best = min(best, m.at(i, j))
+	}
+	return best
+}
+
+// keystrokeCosts is the CostModel used by KeystrokeDistance, in which keeping
+// a character costs one keystroke, to move the cursor past it.
+type keystrokeCosts struct{}
+
+// Insert implements the CostModel interface.
+func (keystrokeCosts) Insert(r rune) int { return 1 }
+
+// Remove implements the CostModel interface.
+func (keystrokeCosts) Remove(r rune) int { return 1 }
+
+// Swap implements the CostModel interface.
+func (keystrokeCosts) Swap(from, to rune) int { return 2 }
+
+// Match implements the CostModel interface.
+func (keystrokeCosts) Match(r rune) int { return 1 }
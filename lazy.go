@@ -0,0 +1,48 @@
+package levenshtein
+
+import "sync"
+
+// LazyMatrix is an edit matrix which is only built when it is first needed.
+// It is safe for concurrent use by multiple goroutines.
+type LazyMatrix struct {
+	source, target string
+	options        []Option
+	once           sync.Once
+	m              *Matrix
+}
+
+// LazyBuild returns an edit matrix for the two strings which is only built
+// (and filled) the first time one of its methods is called, so that many
+// matrices can be created cheaply, and only those which turn out to be needed
+// are calculated - e.g. in a pipeline which may be cancelled. The options are
+// not applied until the matrix is built.
+func LazyBuild(source, target string, options ...Option) *LazyMatrix {
+	return &LazyMatrix{
+		source:  source,
+		target:  target,
+		options: options,
+	}
+}
+
+// Matrix builds the edit matrix, if it has not already been built, and
+// returns it.
+func (l *LazyMatrix) Matrix() *Matrix {
+	l.once.Do(func() {
+		l.m = Build(l.source, l.target, l.options...)
+		l.options = nil
+	})
+	return l.m
+}
+
+// Distance returns the edit distance between the two strings, building the
+// edit matrix if necessary. It is equivalent to l.Matrix().Distance().
+func (l *LazyMatrix) Distance() int {
+	return l.Matrix().Distance()
+}
+
+// Operations returns a minimal list of edit operations required to transform
+// the source string into the target string, building the edit matrix if
+// necessary. It is equivalent to l.Matrix().Operations().
+func (l *LazyMatrix) Operations() []Operation {
+	return l.Matrix().Operations()
+}
@@ -0,0 +1,61 @@
+package levenshtein_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func TestLazyBuild(t *testing.T) {
+	for _, pair := range benchmarkPairs {
+		// Options are applied when the matrix is built, so counting them
+		// counts the builds
+		builds := 0
+		count := func(m *levenshtein.Matrix) { builds++ }
+
+		lazy := levenshtein.LazyBuild(pair[0], pair[1], count, levenshtein.SetSwapCost(2))
+		if builds != 0 {
+			t.Fatalf("LazyBuild(%q, %q) built the matrix eagerly", pair[0], pair[1])
+		}
+
+		m := levenshtein.Build(pair[0], pair[1], levenshtein.SetSwapCost(2))
+		if dist := lazy.Distance(); dist != m.Distance() {
+			t.Errorf("LazyBuild(%q, %q).Distance() = %d, expected %d", pair[0], pair[1], dist, m.Distance())
+		}
+		if ops := lazy.Operations(); !reflect.DeepEqual(ops, m.Operations()) {
+			t.Errorf("LazyBuild(%q, %q).Operations() = %v, expected %v", pair[0], pair[1], ops, m.Operations())
+		}
+		lazy.Distance()
+		if builds != 1 {
+			t.Errorf("LazyBuild(%q, %q) built the matrix %d times, expected once", pair[0], pair[1], builds)
+		}
+	}
+}
+
+func TestLazyBuildConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	builds := 0
+	count := func(m *levenshtein.Matrix) {
+		mu.Lock()
+		defer mu.Unlock()
+		builds++
+	}
+
+	lazy := levenshtein.LazyBuild("kitten", "sitting", count)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if dist := lazy.Distance(); dist != 3 {
+				t.Errorf("Distance() = %d, expected 3", dist)
+			}
+		}()
+	}
+	wg.Wait()
+	if builds != 1 {
+		t.Errorf("matrix was built %d times, expected once", builds)
+	}
+}
@@ -0,0 +1,222 @@
+package levenshtein
+
+// MaxInserts is an option which limits the number of insertions in the list
+// of edit operations to n, for constrained correction (e.g. "at most one
+// insertion"). Alignments which would need more insertions are treated as
+// infeasible, so the distance may be greater than without the limit, and if
+// the target string cannot be reached at all within the limits (e.g. because
+// it is more than n characters longer than the source string), Distance
+// returns -1, and there are no operations. (With WordBoundaryBonus, -1 can
+// also be a real distance, but only if there are operations.) A negative n
+// removes the limit.
+//
+// Limiting the number of operations requires the matrix to track how many
+// operations of each type have been made on the way to each cell, which adds
+// an extra dimension for the number of removals (which also determines the
+// number of insertions), and another for the number of swaps. This multiplies
+// the time and memory required by up to min(n, len(source))+1 for each limited
+// dimension - e.g. a matrix with both MaxRemoves(2) and MaxSwaps(2) takes up
+// to nine times as long to build. The limits are ignored for matrices built
+// with SetAffineGap or ConfusionPairs, and are not taken into account by
+// CellOrigin or UniquePath.
+func MaxInserts(n int) Option {
+	return func(m *Matrix) {
+		if n < 0 {
+			n = -1
+		}
+		m.maxInserts = n
+	}
+}
+
+// MaxRemoves is an option which limits the number of removals in the list of
+// edit operations to n, treating alignments which would need more removals as
+// infeasible. A negative n removes the limit. See MaxInserts for details.
+func MaxRemoves(n int) Option {
+	return func(m *Matrix) {
+		if n < 0 {
+			n = -1
+		}
+		m.maxRemoves = n
+	}
+}
+
+// MaxSwaps is an option which limits the number of swaps in the list of edit
+// operations to n, treating alignments which would need more swaps as
+// infeasible. A negative n removes the limit. See MaxInserts for details.
+func MaxSwaps(n int) Option {
+	return func(m *Matrix) {
+		if n < 0 {
+			n = -1
+		}
+		m.maxSwaps = n
+	}
+}
+
+// limited reports whether the matrix is filled subject to limits on the
+// number of operations of each type.
+func (m *Matrix) limited() bool {
+	return (m.maxInserts >= 0 || m.maxRemoves >= 0 || m.maxSwaps >= 0) && !m.affine && m.confusions == nil
+}
+
+// infeasible reports whether the target string cannot be reached within the
+// limits on the number of operations, in which case there are no operations.
+func (m *Matrix) infeasible() bool {
+	return m.limited() && !m.reachable(len(m.source), len(m.target))
+}
+
+// reachable reports whether cell (i, j) of a matrix filled subject to limits
+// on the number of operations can be reached within the limits. The cell
+// itself holds -1 if it cannot, but with WordBoundaryBonus, -1 can also be
+// the cost of reaching it, so the layers are checked instead.
+func (m *Matrix) reachable(i, j int) bool {
+	for r := 0; r < m.limitRemoves; r++ {
+		for s := 0; s < m.limitSwaps; s++ {
+			if m.limitLayers[m.limitIndex(i, j, r, s)] < gapInfinity {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// countsRemoves reports whether the number of removals made on the way to
+// each cell is tracked, which is needed to limit either the removals or the
+// insertions, since the number of insertions made on the way to cell (i, j)
+// is always j - i plus the number of removals.
+func (m *Matrix) countsRemoves() bool {
+	return m.maxRemoves >= 0 || m.maxInserts >= 0
+}
+
+// fillLimited fills the matrix subject to the limits on the number of
+// operations of each type. The layers hold the best cost of reaching each
+// cell having made each possible number of removals and swaps, while the
+// matrix itself holds the best cost overall, or -1 if the cell cannot be
+// reached within the limits.
+func (m *Matrix) fillLimited() {
+	m.limitRemoves, m.limitSwaps = 1, 1
+	if m.countsRemoves() {
+		m.limitRemoves = len(m.source) + 1
+		if m.maxRemoves >= 0 && m.maxRemoves < len(m.source) {
+			m.limitRemoves = m.maxRemoves + 1
+		}
+	}
+	if m.maxSwaps >= 0 {
+		m.limitSwaps = min(len(m.source), len(m.target), m.maxSwaps) + 1
+	}
+	m.limitLayers = make([]int, (len(m.source)+1)*(len(m.target)+1)*m.limitRemoves*m.limitSwaps)
+
+	for i := 0; i <= len(m.source); i++ {
+		for j := 0; j <= len(m.target); j++ {
+			best := gapInfinity
+			for r := 0; r < m.limitRemoves; r++ {
+				for s := 0; s < m.limitSwaps; s++ {
+					val := m.limitCell(i, j, r, s)
+					m.limitLayers[m.limitIndex(i, j, r, s)] = val
+					best = min(best, val)
+				}
+			}
+			if best == gapInfinity {
+				best = -1
+			}
+			m.matrix[i][j] = best
+		}
+	}
+}
+
+// limitIndex returns the index in the layers of cell (i, j), having made r
+// removals and s swaps.
+func (m *Matrix) limitIndex(i, j, r, s int) int {
+	return ((i*(len(m.target)+1)+j)*m.limitRemoves+r)*m.limitSwaps + s
+}
+
+// limitCell calculates the best cost of reaching cell (i, j) having made r
+// removals and s swaps, or gapInfinity if it cannot be reached that way within
+// the limits.
+func (m *Matrix) limitCell(i, j, r, s int) int {
+	if i == 0 && j == 0 {
+		if r == 0 && s == 0 {
+			return 0
+		}
+		return gapInfinity
+	}
+	if m.countsRemoves() && m.maxInserts >= 0 && j-i+r > m.maxInserts {
+		return gapInfinity // Too many insertions
+	}
+
+	best := gapInfinity
+	for _, opType := range defaultOpOrder {
+		pi, pj, pr, ps, cost, ok := m.limitStep(opType, i, j, r, s)
+		if !ok {
+			continue
+		}
+		if prev := m.limitLayers[m.limitIndex(pi, pj, pr, ps)]; prev < gapInfinity {
+			best = min(best, prev+cost)
+		}
+	}
+	return best
+}
+
+// limitStep returns the cell and the numbers of removals and swaps from which
+// cell (i, j), having made r removals and s swaps, is reached by an operation
+// of the given type, along with the cost of the operation, or false if it
+// cannot be reached by an operation of that type.
+func (m *Matrix) limitStep(opType OpType, i, j, r, s int) (pi, pj, pr, ps, cost int, ok bool) {
+	switch {
+	case opType == Insert && j > 0:
+		return i, j - 1, r, s, m.insert(j), true
+	case opType == Remove && i > 0:
+		if m.countsRemoves() {
+			r--
+		}
+		return i - 1, j, r, s, m.remove(i), r >= 0
+	case opType == Swap && i > 0 && j > 0 && m.source[i-1] != m.target[j-1]:
+		if m.maxSwaps >= 0 {
+			s--
+		}
+		return i - 1, j - 1, r, s, m.swap(i, j), s >= 0
+	case opType == Keep && i > 0 && j > 0 && m.source[i-1] == m.target[j-1]:
+		return i - 1, j - 1, r, s, m.swap(i, j), true
+	default:
+		return 0, 0, 0, 0, 0, false
+	}
+}
+
+// walkLimited is the equivalent of walk for matrices filled subject to limits
+// on the number of operations of each type. If cell (i, j) cannot be reached
+// within the limits, there are no operations to visit.
+func (m *Matrix) walkLimited(i, j int, visit func(op Operation) bool) bool {
+	// Start from the cheapest combination of removals and swaps
+	r, s, best := -1, -1, gapInfinity
+	for rr := 0; rr < m.limitRemoves; rr++ {
+		for ss := 0; ss < m.limitSwaps; ss++ {
+			if val := m.limitLayers[m.limitIndex(i, j, rr, ss)]; val < best {
+				r, s, best = rr, ss, val
+			}
+		}
+	}
+	if r < 0 {
+		return true
+	}
+
+	var ops []Operation
+	for i > 0 || j > 0 {
+		val := m.limitLayers[m.limitIndex(i, j, r, s)]
+		for _, opType := range m.opOrder() {
+			pi, pj, pr, ps, cost, ok := m.limitStep(opType, i, j, r, s)
+			if !ok {
+				continue
+			}
+			if prev := m.limitLayers[m.limitIndex(pi, pj, pr, ps)]; prev < gapInfinity && prev+cost == val {
+				ops = append(ops, m.operation(opType, i, j, val))
+				i, j, r, s = pi, pj, pr, ps
+				break
+			}
+		}
+	}
+	for k := len(ops) - 1; k >= 0; k-- {
+		if !visit(ops[k]) {
+			return false
+		}
+	}
+	return true
+}
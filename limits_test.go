@@ -0,0 +1,138 @@
+package levenshtein_test
+
+import (
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func TestOperationLimits(t *testing.T) {
+	tests := []struct {
+		source, target string
+		options        []levenshtein.Option
+		dist           int
+		ops            string // Operation types: Insert, Remove, Keep, Swap
+	}{
+		{"abcd", "bcde", nil, 2, "RKKKI"},
+		// Without removals, every character must be swapped
+		{"abcd", "bcde", []levenshtein.Option{levenshtein.MaxRemoves(0)}, 4, "SSSS"},
+		{"abcd", "bcde", []levenshtein.Option{levenshtein.MaxInserts(0)}, 4, "SSSS"},
+		{"abcd", "bcde", []levenshtein.Option{levenshtein.MaxRemoves(1)}, 2, "RKKKI"},
+		{"abcd", "bcde", []levenshtein.Option{levenshtein.MaxRemoves(-1)}, 2, "RKKKI"},
+		{"horse", "arose", []levenshtein.Option{levenshtein.MaxSwaps(0)}, 4, "RRIKIKK"},
+		{"horse", "arose", []levenshtein.Option{levenshtein.MaxRemoves(0)}, 3, "SSSKK"},
+		{"kitten", "sitting", []levenshtein.Option{levenshtein.MaxSwaps(1)}, 4, "SKKKRIKI"},
+		// The target can't be reached at all within the limits
+		{"abc", "ab", []levenshtein.Option{levenshtein.MaxRemoves(0)}, -1, ""},
+		{"horse", "arose", []levenshtein.Option{levenshtein.MaxRemoves(0), levenshtein.MaxSwaps(0)}, -1, ""},
+		{"abc", "xyz", []levenshtein.Option{levenshtein.MaxSwaps(1), levenshtein.MaxInserts(1)}, -1, ""},
+		{"", "a", []levenshtein.Option{levenshtein.MaxInserts(0)}, -1, ""},
+		{"", "", []levenshtein.Option{levenshtein.MaxInserts(0)}, 0, ""},
+	}
+	for _, test := range tests {
+		matrix := levenshtein.Build(test.source, test.target, test.options...)
+		if dist := matrix.Distance(); dist != test.dist {
+			t.Errorf("%q, %q: Distance() = %d, want %d", test.source, test.target, dist, test.dist)
+		}
+
+		ops := matrix.Operations()
+		var types []byte
+		for _, op := range ops {
+			types = append(types, "IRKS"[op.Type])
+		}
+		if string(types) != test.ops {
+			t.Errorf("%q, %q: Operations() types = %q, want %q", test.source, test.target, types, test.ops)
+		}
+		if test.dist < 0 {
+			continue
+		}
+		if result, err := levenshtein.Apply(test.source, ops); err != nil || result != test.target {
+			t.Errorf("%q, %q: Apply(Operations()) = %q, %v", test.source, test.target, result, err)
+		}
+		if err := levenshtein.VerifyResults(ops); err != nil {
+			t.Errorf("%q, %q: VerifyResults(Operations()): %v", test.source, test.target, err)
+		}
+		if len(ops) > 0 && ops[len(ops)-1].CumulativeCost != test.dist {
+			t.Errorf("%q, %q: final CumulativeCost = %d, want %d", test.source, test.target, ops[len(ops)-1].CumulativeCost, test.dist)
+		}
+
+		// The distance is the same without a full matrix
+		if dist := levenshtein.Distance(test.source, test.target, test.options...); dist != test.dist {
+			t.Errorf("%q, %q: Distance = %d, want %d", test.source, test.target, dist, test.dist)
+		}
+	}
+}
+
+func TestOperationLimitsWithin(t *testing.T) {
+	// A target which can't be reached within the limits is never within max
+	if levenshtein.Compile("abc", levenshtein.MaxRemoves(0)).Within("ab", 10) {
+		t.Error("Within(\"abc\", \"ab\", 10) with MaxRemoves(0) = true, expected false")
+	}
+	if matches := levenshtein.WithinDistance("abc", []string{"ab", "abcd"}, 2, levenshtein.MaxRemoves(0)); len(matches) != 1 || matches[0].Candidate != "abcd" {
+		t.Errorf("WithinDistance with MaxRemoves(0) = %v, expected only \"abcd\"", matches)
+	}
+}
+
+func TestOperationLimitsInfeasibleAnalysis(t *testing.T) {
+	// None of the analyses of the operations apply when there are none
+	matrix := levenshtein.Build("abc", "", levenshtein.MaxRemoves(0))
+	if matrix.UniquePath() {
+		t.Error("UniquePath() = true, want false")
+	}
+	if rel := matrix.Relationship(); rel != levenshtein.Infeasible {
+		t.Errorf("Relationship() = %v, want %v", rel, levenshtein.Infeasible)
+	}
+	if change := matrix.NetLengthChange(); change != -3 {
+		t.Errorf("NetLengthChange() = %d, want -3", change)
+	}
+	if explanation := matrix.Explain(); explanation != "infeasible" {
+		t.Errorf("Explain() = %q, want \"infeasible\"", explanation)
+	}
+	if inserted, removed := matrix.ChangedRunes(); inserted != nil || removed != nil {
+		t.Errorf("ChangedRunes() = %q, %q, want none", inserted, removed)
+	}
+	if rate := matrix.SubstitutionRate(); rate != 0 {
+		t.Errorf("SubstitutionRate() = %v, want 0", rate)
+	}
+	if density := matrix.SourceEditDensity(); density != nil {
+		t.Errorf("SourceEditDensity() = %v, want nil", density)
+	}
+	if paths, truncated := matrix.AllOperationsLimited(10); len(paths) != 0 || truncated {
+		t.Errorf("AllOperationsLimited(10) = %v, %t, want no paths", paths, truncated)
+	}
+
+	// A feasible path is still unique once it reaches the edge of the matrix
+	if !levenshtein.Build("abc", "", levenshtein.MaxRemoves(3)).UniquePath() {
+		t.Error("UniquePath() with MaxRemoves(3) = false, want true")
+	}
+}
+
+func TestOperationLimitsWordBoundaryBonus(t *testing.T) {
+	// With the bonus, -1 is a real distance, which must not be mistaken for
+	// the target being unreachable within the limits
+	options := []levenshtein.Option{levenshtein.WordBoundaryBonus(3), levenshtein.MaxRemoves(0)}
+	matrix := levenshtein.Build("ab", "ab c", options...)
+	if dist := matrix.Distance(); dist != -1 {
+		t.Fatalf("Distance() = %d, want -1", dist)
+	}
+	if ops := matrix.Operations(); len(ops) != 4 {
+		t.Errorf("Operations() = %v, want 4 operations", ops)
+	}
+	if rel := matrix.Relationship(); rel != levenshtein.Insertion {
+		t.Errorf("Relationship() = %v, want %v", rel, levenshtein.Insertion)
+	}
+	if !levenshtein.Compile("ab", options...).Within("ab c", 0) {
+		t.Error("Within(\"ab\", \"ab c\", 0) = false, want true")
+	}
+	if _, dist, ok := levenshtein.DistanceWithinMatrix("ab", "ab c", 0, options...); dist != -1 || !ok {
+		t.Errorf("DistanceWithinMatrix = %d, %t, want -1, true", dist, ok)
+	}
+	if percent := levenshtein.SimilarityPercent("ab", "ab c", options...); percent != 100 {
+		t.Errorf("SimilarityPercent = %d, want 100", percent)
+	}
+
+	// An unreachable target is still reported as such
+	if percent := levenshtein.SimilarityPercent("abc", "", options...); percent != 0 {
+		t.Errorf("SimilarityPercent(\"abc\", \"\") = %d, want 0", percent)
+	}
+}
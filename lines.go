@@ -0,0 +1,73 @@
+package levenshtein
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LineOperation represents one of the operations performed on the lines of a
+// source text during the process of converting it into a target text. It is
+// the line-level equivalent of an Operation.
+type LineOperation struct {
+	Type  OpType
+	Line  string
+	Index int
+}
+
+// String returns the string representation of a line operation.
+func (o LineOperation) String() string {
+	return fmt.Sprintf("%6s line %d: %s", o.Type, o.Index, o.Line)
+}
+
+// LineOperations returns a minimal list of line-level edit operations required
+// to transform the source text into the target text, treating each line as a
+// single unit which can be inserted, removed, kept, or swapped for another
+// line. A trailing newline does not produce an extra empty line. The Index of
+// each operation has the same meaning as for Operation, but counts lines
+// rather than characters.
+func LineOperations(source, target string) []LineOperation {
+	sourceLines, targetLines := splitLines(source), splitLines(target)
+
+	// Assign each distinct line a unique rune, so that the lines can be
+	// compared using an ordinary edit matrix
+	ids := map[string]rune{}
+	lines := []string{}
+	toRunes := func(ls []string) []rune {
+		runes := make([]rune, len(ls))
+		for i, line := range ls {
+			id, ok := ids[line]
+			if !ok {
+				id = rune(len(lines))
+				ids[line] = id
+				lines = append(lines, line)
+			}
+			runes[i] = id
+		}
+		return runes
+	}
+
+	m := newConfig([]Option{SetResultMode(ResultNone)})
+	m.source = toRunes(sourceLines)
+	m.target = toRunes(targetLines)
+	m.allocate()
+	m.fill()
+
+	ops := m.Operations()
+	lineOps := make([]LineOperation, len(ops))
+	for i, op := range ops {
+		lineOps[i] = LineOperation{
+			Type:  op.Type,
+			Line:  lines[op.Char],
+			Index: op.Index,
+		}
+	}
+	return lineOps
+}
+
+// splitLines splits text into lines, ignoring any trailing newline.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
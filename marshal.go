@@ -0,0 +1,240 @@
+package levenshtein
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// binaryVersion is the version of the binary encoding produced by
+// MarshalBinary. It must be incremented whenever the layout of the encoding
+// changes, so that encodings of the old layout are rejected rather than
+// misread.
+const binaryVersion = 2
+
+var (
+	_ encoding.BinaryMarshaler   = (*Matrix)(nil)
+	_ encoding.BinaryUnmarshaler = (*Matrix)(nil)
+)
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface, encoding
+// the source and target strings, the costs, and the cells of the matrix, so
+// that the matrix can be cached and later restored with UnmarshalBinary.
+// Cost functions cannot be encoded, so an error is returned for matrices
+// built with the SetSwapCostFunc or UseCostModel options.
+func (m *Matrix) MarshalBinary() ([]byte, error) {
+	if m.swapFunc != nil {
+		return nil, errors.New("levenshtein: cannot marshal matrix with a swap cost function")
+	}
+	if m.costs != nil {
+		return nil, errors.New("levenshtein: cannot marshal matrix with a cost model")
+	}
+
+	data := []byte{binaryVersion}
+	data = appendRunes(data, m.source)
+	data = appendRunes(data, m.target)
+	data = binary.AppendVarint(data, int64(m.insertCost))
+	data = binary.AppendVarint(data, int64(m.removeCost))
+	data = binary.AppendVarint(data, int64(m.swapCost))
+	data = binary.AppendVarint(data, int64(m.markCost))
+	data = appendBool(data, m.markCostOK)
+	data = binary.AppendVarint(data, int64(m.caseCost))
+	data = appendBool(data, m.caseCostOK)
+	data = binary.AppendVarint(data, int64(m.wildcard))
+	data = appendBool(data, m.wildcardOK)
+	data = binary.AppendVarint(data, int64(m.firstCost))
+	data = binary.AppendUvarint(data, uint64(len(m.weights)))
+	for _, w := range m.weights {
+		data = binary.AppendUvarint(data, math.Float64bits(w))
+	}
+	data = binary.AppendVarint(data, int64(m.confCost))
+	data = binary.AppendUvarint(data, uint64(len(m.confusions)))
+	for _, c := range m.confusions {
+		data = appendRunes(data, c.from)
+		data = appendRunes(data, c.to)
+	}
+	data = appendBool(data, m.affine)
+	data = binary.AppendVarint(data, int64(m.gapOpen))
+	data = binary.AppendVarint(data, int64(m.gapExtend))
+	data = binary.AppendVarint(data, int64(m.resultMode))
+	data = appendBool(data, m.trace)
+	data = binary.AppendVarint(data, int64(m.prefer))
+	data = appendBool(data, m.preferOK)
+	data = binary.AppendVarint(data, int64(m.maxInserts))
+	data = binary.AppendVarint(data, int64(m.maxRemoves))
+	data = binary.AppendVarint(data, int64(m.maxSwaps))
+	data = binary.AppendVarint(data, int64(m.bonus))
+	for i := 0; i <= len(m.source); i++ {
+		for j := 0; j <= len(m.target); j++ {
+			data = binary.AppendVarint(data, int64(m.at(i, j)))
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// restoring a matrix encoded by MarshalBinary.
+func (m *Matrix) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return errors.New("levenshtein: unsupported binary encoding version")
+	}
+	d := decoder{data: data[1:]}
+
+	var u Matrix
+	u.source = d.runes()
+	u.target = d.runes()
+	u.insertCost = d.int()
+	u.removeCost = d.int()
+	u.swapCost = d.int()
+	u.markCost = d.int()
+	u.markCostOK = d.bool()
+	u.caseCost = d.int()
+	u.caseCostOK = d.bool()
+	u.wildcard = rune(d.int())
+	u.wildcardOK = d.bool()
+	u.firstCost = d.int()
+	if n := d.uint(); n > 0 {
+		u.weights = make([]float64, n)
+		for k := range u.weights {
+			u.weights[k] = math.Float64frombits(d.uint64())
+		}
+	}
+	u.confCost = d.int()
+	if n := d.uint(); n > 0 {
+		u.confusions = make([]confusion, 0, n)
+		for k := uint64(0); k < n && d.err == nil; k++ {
+			u.confusions = append(u.confusions, confusion{from: d.runes(), to: d.runes()})
+		}
+	}
+	u.affine = d.bool()
+	u.gapOpen = d.int()
+	u.gapExtend = d.int()
+	u.resultMode = ResultMode(d.int())
+	u.trace = d.bool()
+	u.prefer = OpType(d.int())
+	u.preferOK = d.bool()
+	u.maxInserts = d.int()
+	u.maxRemoves = d.int()
+	u.maxSwaps = d.int()
+	u.bonus = d.int()
+	if d.err != nil {
+		return d.err
+	}
+
+	// Each cell takes at least one byte, so a short encoding is rejected
+	// before the matrix is allocated
+	if cells := uint64(len(u.source)+1) * uint64(len(u.target)+1); uint64(len(d.data)) < cells {
+		return errors.New("levenshtein: invalid binary encoding")
+	}
+	u.allocate()
+	for i := 0; i <= len(u.source); i++ {
+		for j := 0; j <= len(u.target); j++ {
+			u.set(i, j, d.int())
+		}
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.data) > 0 {
+		return fmt.Errorf("levenshtein: %d unexpected trailing bytes", len(d.data))
+	}
+	if u.affine || u.limited() || u.trace {
+		// The gap layers, limit layers, and cell origins are not encoded,
+		// since they can be recalculated
+		u.fill()
+	}
+
+	*m = u
+	return nil
+}
+
+func appendRunes(data []byte, runes []rune) []byte {
+	data = binary.AppendUvarint(data, uint64(len(runes)))
+	for _, r := range runes {
+		data = binary.AppendVarint(data, int64(r))
+	}
+	return data
+}
+
+func appendBool(data []byte, b bool) []byte {
+	if b {
+		return append(data, 1)
+	}
+	return append(data, 0)
+}
+
+// decoder reads values encoded by MarshalBinary, recording the first error
+// encountered.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) int() int {
+	if d.err != nil {
+		return 0
+	}
+	val, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = errors.New("levenshtein: invalid binary encoding")
+		return 0
+	}
+	d.data = d.data[n:]
+	return int(val)
+}
+
+// uint reads a count of items, each of which must take at least one byte.
+func (d *decoder) uint() uint64 {
+	val := d.uint64()
+	if d.err == nil && val > uint64(len(d.data)) {
+		d.err = errors.New("levenshtein: invalid binary encoding")
+		return 0
+	}
+	return val
+}
+
+func (d *decoder) uint64() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	val, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errors.New("levenshtein: invalid binary encoding")
+		return 0
+	}
+	d.data = d.data[n:]
+	return val
+}
+
+func (d *decoder) bool() bool {
+	if d.err != nil {
+		return false
+	}
+	if len(d.data) == 0 || d.data[0] > 1 {
+		d.err = errors.New("levenshtein: invalid binary encoding")
+		return false
+	}
+	b := d.data[0] == 1
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *decoder) runes() []rune {
+	if d.err != nil {
+		return nil
+	}
+	length, n := binary.Uvarint(d.data)
+	if n <= 0 || length > uint64(len(d.data)) {
+		d.err = errors.New("levenshtein: invalid binary encoding")
+		return nil
+	}
+	d.data = d.data[n:]
+
+	runes := make([]rune, length)
+	for i := range runes {
+		runes[i] = rune(d.int())
+	}
+	return runes
+}
@@ -0,0 +1,102 @@
+package levenshtein_test
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	for _, test := range []struct {
+		source, target string
+		options        []levenshtein.Option
+	}{
+		{"horse", "arose", nil},
+		{"", "", nil},
+		{"", "abc", nil},
+		{"héllo", "wörld", []levenshtein.Option{
+			levenshtein.SetInsertCost(3),
+			levenshtein.SetRemoveCost(200),
+			levenshtein.SetSwapCost(0),
+		}},
+		{"resume", "résumé", []levenshtein.Option{
+			levenshtein.CombiningMarksCost(0),
+			levenshtein.SetResultMode(levenshtein.ResultFinalOnly),
+		}},
+		{"a?c", "axc", []levenshtein.Option{levenshtein.WildcardRune('?')}},
+		{"ab", "axyzb", []levenshtein.Option{levenshtein.SetAffineGap(3, 1)}},
+		{"stem", "steam", []levenshtein.Option{levenshtein.SetPositionWeights([]float64{3, 2.5, 0.5})}},
+		{"abcd", "bcde", []levenshtein.Option{levenshtein.MaxRemoves(0), levenshtein.MaxSwaps(3)}},
+		{"rnodern", "modern", []levenshtein.Option{
+			levenshtein.ConfusionPairs(map[string]string{"rn": "m", "l": "1"}, 0),
+		}},
+	} {
+		matrix := levenshtein.Build(test.source, test.target, test.options...)
+		data, err := matrix.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary(%q, %q): %v", test.source, test.target, err)
+		}
+
+		var restored levenshtein.Matrix
+		if err := restored.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary(%q, %q): %v", test.source, test.target, err)
+		}
+		if got, want := restored.Distance(), matrix.Distance(); got != want {
+			t.Errorf("%q, %q: restored Distance() = %d, want %d", test.source, test.target, got, want)
+		}
+		if got, want := restored.Operations(), matrix.Operations(); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q, %q: restored Operations() = %v, want %v", test.source, test.target, got, want)
+		}
+		if got, want := restored.String(), matrix.String(); got != want {
+			t.Errorf("%q, %q: restored String() = %q, want %q", test.source, test.target, got, want)
+		}
+
+		// Truncated data must be rejected rather than panicking
+		for n := 0; n < len(data); n++ {
+			if err := restored.UnmarshalBinary(data[:n]); err == nil {
+				t.Errorf("%q, %q: UnmarshalBinary(data[:%d]) succeeded, want error", test.source, test.target, n)
+			}
+		}
+	}
+}
+
+func TestMarshalBinarySwapFunc(t *testing.T) {
+	matrix := levenshtein.Build("hellp", "hello", levenshtein.SetSwapCostFunc(levenshtein.QWERTYSwapCost))
+	if _, err := matrix.MarshalBinary(); err == nil {
+		t.Error("MarshalBinary succeeded, want error")
+	}
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	data, err := levenshtein.Build("kitten", "sitting").MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	var restored levenshtein.Matrix
+
+	// Encodings of other versions of the layout are rejected
+	old := append([]byte{1}, data[1:]...)
+	if err := restored.UnmarshalBinary(old); err == nil {
+		t.Error("UnmarshalBinary of version 1 succeeded, want error")
+	}
+
+	// Too few bytes for the cells are rejected before allocating the matrix
+	long := strings.Repeat("a", 500)
+	data, err = levenshtein.Build(long, strings.Repeat("b", 500)).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	err = restored.UnmarshalBinary(data[:len(data)/4])
+	runtime.ReadMemStats(&after)
+	if err == nil {
+		t.Error("UnmarshalBinary of truncated cells succeeded, want error")
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 100000 {
+		t.Errorf("UnmarshalBinary of truncated cells allocated %d bytes, want at most 100000", allocated)
+	}
+}
@@ -0,0 +1,35 @@
+package levenshtein
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NumericDistance returns the edit distance between two numeric identifiers,
+// ignoring formatting noise: every character which is not a digit (e.g. a
+// thousands separator, dash, or space) is removed, and then any leading zeros
+// are removed, so "007" and "7" are at distance 0, as are "1,234" and "1234".
+// A number made up entirely of zeros is normalized to "0", so that it still
+// differs from a string with no digits at all, which is normalized to the
+// empty string.
+func NumericDistance(a, b string) int {
+	return Distance(canonicalNumber(a), canonicalNumber(b))
+}
+
+// canonicalNumber removes the non-digit characters and leading zeros from the
+// string, as described by NumericDistance.
+func canonicalNumber(s string) string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+	if digits == "" {
+		return ""
+	}
+	if trimmed := strings.TrimLeft(digits, "0"); trimmed != "" {
+		return trimmed
+	}
+	return "0"
+}
@@ -0,0 +1,267 @@
+package levenshtein
+
+import "fmt"
+
+// OpCode describes how to turn a range of the source string into a range of
+// the target string, in the same form as the opcodes returned by
+// get_opcodes() in Python's difflib. Tag is one of "equal", "replace",
+// "insert", or "delete", and the ranges are half-open, counted in runes.
+type OpCode struct {
+	Tag         string
+	SourceStart int
+	SourceEnd   int
+	TargetStart int
+	TargetEnd   int
+}
+
+// String returns the string representation of an opcode.
+func (o OpCode) String() string {
+	return fmt.Sprintf("%7s source[%d:%d] target[%d:%d]", o.Tag, o.SourceStart, o.SourceEnd, o.TargetStart, o.TargetEnd)
+}
+
+// OpCodes groups the minimal list of edit operations into opcodes, like
+// difflib's get_opcodes(): each run of kept characters becomes an "equal"
+// opcode, and each run of other operations between them becomes a single
+// "replace", "delete", or "insert" opcode, depending on whether it spans
+// characters of both strings, only the source, or only the target. Together,
+// the opcodes cover both strings completely, in order.
+func (m *Matrix) OpCodes() []OpCode {
+	opCodes := []OpCode{}
+	i, j := 0, 0 // Positions in the source and target strings
+	add := func(equal bool, di, dj int) {
+		if n := len(opCodes) - 1; n >= 0 && (opCodes[n].Tag == "equal") == equal {
+			opCodes[n].SourceEnd += di
+			opCodes[n].TargetEnd += dj
+		} else {
+			opCode := OpCode{
+				SourceStart: i,
+				SourceEnd:   i + di,
+				TargetStart: j,
+				TargetEnd:   j + dj,
+			}
+			if equal {
+				opCode.Tag = "equal"
+			}
+			opCodes = append(opCodes, opCode)
+		}
+		i, j = i+di, j+dj
+	}
+
+	m.walk(len(m.source), len(m.target), func(op Operation) bool {
+		switch op.Type {
+		case Keep:
+			add(true, 1, 1)
+		case Swap:
+			add(false, 1, 1)
+		case Insert:
+			add(false, 0, 1)
+		case Remove:
+			add(false, 1, 0)
+		}
+		return true
+	})
+
+	for n := range opCodes {
+		o := &opCodes[n]
+		switch {
+		case o.Tag == "equal":
+		case o.SourceStart == o.SourceEnd:
+			o.Tag = "insert"
+		case o.TargetStart == o.TargetEnd:
+			o.Tag = "delete"
+		default:
+			o.Tag = "replace"
+		}
+	}
+	return opCodes
+}
+
+// Gap is the value of the Source or Target field of an AlignedPair for a
+// character which has no counterpart in the other string.
+const Gap rune = -1
+
+// AlignedPair is one column of the alignment of two strings: a character of
+// the source string and the character of the target string which it is
+// aligned with, along with the operation which relates them. The Source of an
+// insertion and the Target of a removal are Gap.
+type AlignedPair struct {
+	Source rune
+	Target rune
+	Type   OpType
+}
+
+// AlignedPairs returns the alignment of the two strings described by the
+// minimal list of edit operations, as a list of aligned pairs of characters.
+// This is a lower-level view than Operations, for custom renderers: for
+// example, the source and target fields of the pairs can be printed as two
+// parallel lines, with gaps shown as dashes.
+func (m *Matrix) AlignedPairs() []AlignedPair {
+	pairs := make([]AlignedPair, 0, len(m.source)+len(m.target))
+	m.walk(len(m.source), len(m.target), func(op Operation) bool {
+		pair := AlignedPair{Source: Gap, Target: Gap, Type: op.Type}
+		switch op.Type {
+		case Insert:
+			pair.Target = op.Char
+		case Remove:
+			pair.Source = op.Char
+		case Keep:
+			pair.Source, pair.Target = op.Char, op.Char
+		case Swap:
+			pair.Source, pair.Target = op.FromChar, op.Char
+		}
+		pairs = append(pairs, pair)
+		return true
+	})
+	return pairs
+}
+
+// RuneEdit is one step of a position-oriented diff of two slices of runes:
+// an operation, along with the index of the rune of the source slice and the
+// index of the rune of the target slice which it relates. The SourceIdx of an
+// insertion and the TargetIdx of a removal are -1.
+type RuneEdit struct {
+	Type      OpType
+	SourceIdx int
+	TargetIdx int
+}
+
+// RuneDiff returns the minimal list of edit operations required to transform
+// the source slice of runes into the target slice, under the default costs, as
+// a list of edits which refer to the runes of both slices by index. Unlike
+// Operations, it does not reconstruct any intermediate strings, so it is the
+// cheapest structured form of the diff - e.g. for editors which track
+// positions in both texts.
+func RuneDiff(a, b []rune) []RuneEdit {
+	m := newConfig(nil)
+	m.source, m.target = a, b
+	m.allocate()
+	m.fill()
+
+	edits := make([]RuneEdit, 0, len(a)+len(b))
+	i, j := 0, 0 // Positions in the source and target slices
+	m.walk(len(a), len(b), func(op Operation) bool {
+		edit := RuneEdit{Type: op.Type, SourceIdx: -1, TargetIdx: -1}
+		switch op.Type {
+		case Insert:
+			edit.TargetIdx = j
+			j++
+		case Remove:
+			edit.SourceIdx = i
+			i++
+		default:
+			edit.SourceIdx, edit.TargetIdx = i, j
+			i, j = i+1, j+1
+		}
+		edits = append(edits, edit)
+		return true
+	})
+	return edits
+}
+
+// Replacement describes a contiguous region of the source string which is
+// replaced by a region of the target string, along with the positions of the
+// two regions. The ranges are half-open, counted in runes. Either substring
+// may be empty, for a pure insertion or deletion.
+type Replacement struct {
+	Source      string
+	Target      string
+	SourceStart int
+	SourceEnd   int
+	TargetStart int
+	TargetEnd   int
+}
+
+// String returns the string representation of a replacement.
+func (r Replacement) String() string {
+	return fmt.Sprintf("replace %q with %q at source[%d:%d] target[%d:%d]", r.Source, r.Target, r.SourceStart, r.SourceEnd, r.TargetStart, r.TargetEnd)
+}
+
+// Replacements returns the regions of the source string which are changed by
+// the minimal list of edit operations, each along with the region of the
+// target string which replaces it. These are the non-"equal" opcodes returned
+// by OpCodes, so each run of swaps, insertions, and removals between kept
+// characters is reported as a single "replace X with Y" record, which is the
+// most natural form for code-review style display.
+func (m *Matrix) Replacements() []Replacement {
+	replacements := []Replacement{}
+	for _, o := range m.OpCodes() {
+		if o.Tag == "equal" {
+			continue
+		}
+		replacements = append(replacements, Replacement{
+			Source:      string(m.source[o.SourceStart:o.SourceEnd]),
+			Target:      string(m.target[o.TargetStart:o.TargetEnd]),
+			SourceStart: o.SourceStart,
+			SourceEnd:   o.SourceEnd,
+			TargetStart: o.TargetStart,
+			TargetEnd:   o.TargetEnd,
+		})
+	}
+	return replacements
+}
+
+// Variances returns the regions of the target string which differ from the
+// source string (i.e. the runs of inserted and swapped-in characters between
+// kept characters), in order. It can be used to extract the "variable" parts
+// of two mostly-identical strings, such as the version numbers of
+// "file_v1.txt" and "file_v2.txt" (which returns ["2"]). Regions of the source
+// string which are only removed have no counterpart in the target string, so
+// they are left out.
+func (m *Matrix) Variances() []string {
+	variances := []string{}
+	for _, r := range m.Replacements() {
+		if r.Target != "" {
+			variances = append(variances, r.Target)
+		}
+	}
+	return variances
+}
+
+// ContextEdit is an edit operation along with the characters surrounding it
+// in the intermediate result of performing it, for display with some context
+// (e.g. in a code-review UI). For insertions and swaps, Before and After are
+// the characters either side of the character which was inserted or swapped
+// in; for removals, they are the characters either side of the position from
+// which the character was removed.
+type ContextEdit struct {
+	Operation Operation
+	Before    string
+	After     string
+}
+
+// ContextualEdits returns each of the edits (i.e. the operations other than
+// keeps) in the minimal list of edit operations, along with up to window
+// characters of context on each side, taken from the intermediate result of
+// performing the edit. The context is clamped at the start and end of the
+// result, so it may be shorter than window. A negative window is treated as
+// zero.
+func (m *Matrix) ContextualEdits(window int) []ContextEdit {
+	if window < 0 {
+		window = 0
+	}
+	edits := []ContextEdit{}
+	result := append([]rune(nil), m.source...)
+	for _, op := range m.Operations() {
+		result, _ = applyOp(result, op) // The operations always apply cleanly
+		if op.Type == Keep {
+			continue
+		}
+
+		// The range of the result occupied by the edited character, which is
+		// empty for a removal
+		start, end := op.Index, op.Index
+		if op.Type != Remove {
+			end++
+		}
+		before := start - window
+		if before < 0 {
+			before = 0
+		}
+		edits = append(edits, ContextEdit{
+			Operation: op,
+			Before:    string(result[before:start]),
+			After:     string(result[end:min(end+window, len(result))]),
+		})
+	}
+	return edits
+}
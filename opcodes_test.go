@@ -0,0 +1,185 @@
+package levenshtein_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func TestOpCodes(t *testing.T) {
+	// Expected values are the output of difflib.SequenceMatcher.get_opcodes()
+	tests := []struct {
+		source, target string
+		expected       []levenshtein.OpCode
+	}{
+		{"", "", []levenshtein.OpCode{}},
+		{"abc", "abc", []levenshtein.OpCode{{"equal", 0, 3, 0, 3}}},
+		{"", "abc", []levenshtein.OpCode{{"insert", 0, 0, 0, 3}}},
+		{"abc", "", []levenshtein.OpCode{{"delete", 0, 3, 0, 0}}},
+		{"abc", "xyz", []levenshtein.OpCode{{"replace", 0, 3, 0, 3}}},
+		{"qabxcd", "abycdf", []levenshtein.OpCode{
+			{"delete", 0, 1, 0, 0},
+			{"equal", 1, 3, 0, 2},
+			{"replace", 3, 4, 2, 3},
+			{"equal", 4, 6, 3, 5},
+			{"insert", 6, 6, 5, 6},
+		}},
+		{"héllo", "hallo", []levenshtein.OpCode{
+			{"equal", 0, 1, 0, 1},
+			{"replace", 1, 2, 1, 2},
+			{"equal", 2, 5, 2, 5},
+		}},
+	}
+
+	for _, test := range tests {
+		opCodes := levenshtein.Build(test.source, test.target).OpCodes()
+		if !reflect.DeepEqual(opCodes, test.expected) {
+			t.Errorf("OpCodes(%q, %q) = %v, expected %v", test.source, test.target, opCodes, test.expected)
+		}
+	}
+}
+
+func TestAlignedPairs(t *testing.T) {
+	const gap = levenshtein.Gap
+	expected := []levenshtein.AlignedPair{
+		{'h', 'a', levenshtein.Swap},
+		{'o', gap, levenshtein.Remove},
+		{'r', 'r', levenshtein.Keep},
+		{gap, 'o', levenshtein.Insert},
+		{'s', 's', levenshtein.Keep},
+		{'e', 'e', levenshtein.Keep},
+	}
+	if pairs := levenshtein.Build("horse", "arose").AlignedPairs(); !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("AlignedPairs(\"horse\", \"arose\") = %v, expected %v", pairs, expected)
+	}
+
+	if pairs := levenshtein.Build("", "").AlignedPairs(); len(pairs) != 0 {
+		t.Errorf("AlignedPairs(\"\", \"\") = %v, expected no pairs", pairs)
+	}
+}
+
+func TestRuneDiff(t *testing.T) {
+	expected := []levenshtein.RuneEdit{
+		{levenshtein.Swap, 0, 0},
+		{levenshtein.Remove, 1, -1},
+		{levenshtein.Keep, 2, 1},
+		{levenshtein.Insert, -1, 2},
+		{levenshtein.Keep, 3, 3},
+		{levenshtein.Keep, 4, 4},
+	}
+	if edits := levenshtein.RuneDiff([]rune("horse"), []rune("arose")); !reflect.DeepEqual(edits, expected) {
+		t.Errorf("RuneDiff(\"horse\", \"arose\") = %v, expected %v", edits, expected)
+	}
+
+	// Indices count runes, not bytes
+	expected = []levenshtein.RuneEdit{
+		{levenshtein.Keep, 0, 0},
+		{levenshtein.Remove, 1, -1},
+		{levenshtein.Keep, 2, 1},
+		{levenshtein.Insert, -1, 2},
+	}
+	if edits := levenshtein.RuneDiff([]rune("héy"), []rune("hyé")); !reflect.DeepEqual(edits, expected) {
+		t.Errorf("RuneDiff(\"héy\", \"hyé\") = %v, expected %v", edits, expected)
+	}
+
+	if edits := levenshtein.RuneDiff(nil, nil); len(edits) != 0 {
+		t.Errorf("RuneDiff(nil, nil) = %v, expected no edits", edits)
+	}
+}
+
+func TestReplacements(t *testing.T) {
+	tests := []struct {
+		source, target string
+		expected       []levenshtein.Replacement
+	}{
+		{"", "", []levenshtein.Replacement{}},
+		{"abc", "abc", []levenshtein.Replacement{}},
+		{"the quick fox", "the slow fox", []levenshtein.Replacement{
+			{"quick", "slow", 4, 9, 4, 8},
+		}},
+		{"qabxcd", "abycdf", []levenshtein.Replacement{
+			{"q", "", 0, 1, 0, 0},
+			{"x", "y", 3, 4, 2, 3},
+			{"", "f", 6, 6, 5, 6},
+		}},
+		{"héllo wörld", "hallo world", []levenshtein.Replacement{
+			{"é", "a", 1, 2, 1, 2},
+			{"ö", "o", 7, 8, 7, 8},
+		}},
+	}
+
+	for _, test := range tests {
+		replacements := levenshtein.Build(test.source, test.target).Replacements()
+		if !reflect.DeepEqual(replacements, test.expected) {
+			t.Errorf("Replacements(%q, %q) = %v, expected %v", test.source, test.target, replacements, test.expected)
+		}
+	}
+}
+
+func TestVariances(t *testing.T) {
+	tests := []struct {
+		source, target string
+		expected       []string
+	}{
+		{"", "", []string{}},
+		{"file_v1.txt", "file_v1.txt", []string{}},
+		{"file_v1.txt", "file_v2.txt", []string{"2"}},
+		{"file_v1.txt", "file_v12.txt", []string{"2"}},
+		{"report-2023-01.csv", "report-2024-11.csv", []string{"4", "1"}},
+		{"id=abc;name=bob", "id=xyz;name=sue", []string{"xyz", "sue"}},
+		// Removed regions have no counterpart in the target
+		{"file_v1_old.txt", "file_v2.txt", []string{"2"}},
+	}
+
+	for _, test := range tests {
+		variances := levenshtein.Build(test.source, test.target).Variances()
+		if !reflect.DeepEqual(variances, test.expected) {
+			t.Errorf("Variances(%q, %q) = %q, expected %q", test.source, test.target, variances, test.expected)
+		}
+	}
+}
+
+func TestContextualEdits(t *testing.T) {
+	type context struct {
+		opType        levenshtein.OpType
+		before, after string
+	}
+	tests := []struct {
+		source, target string
+		window         int
+		expected       []context
+	}{
+		// An edit near the start is clamped to the start of the string
+		{"hello world", "jello world", 3, []context{{levenshtein.Swap, "", "ell"}}},
+		// An edit in the middle has context on both sides
+		{"hello world", "hello, world", 3, []context{{levenshtein.Insert, "llo", " wo"}}},
+		{"hello world", "helo world", 3, []context{{levenshtein.Remove, "hel", "o w"}}},
+		// An edit near the end is clamped to the end of the string
+		{"hello world", "hello worlds", 4, []context{{levenshtein.Insert, "orld", ""}}},
+		// Context is taken from the intermediate result, so it includes
+		// earlier edits, and source characters which are yet to be edited
+		{"abcdef", "xbcdey", 2, []context{
+			{levenshtein.Swap, "", "bc"},
+			{levenshtein.Swap, "de", ""},
+		}},
+		{"horse", "arose", 10, []context{
+			{levenshtein.Swap, "", "orse"},
+			{levenshtein.Remove, "a", "rse"},
+			{levenshtein.Insert, "ar", "se"},
+		}},
+		{"abc", "abc", 2, nil},
+		{"abc", "abd", 0, []context{{levenshtein.Swap, "", ""}}},
+		{"abc", "abd", -1, []context{{levenshtein.Swap, "", ""}}},
+	}
+	for _, test := range tests {
+		edits := levenshtein.Build(test.source, test.target).ContextualEdits(test.window)
+		var contexts []context
+		for _, edit := range edits {
+			contexts = append(contexts, context{edit.Operation.Type, edit.Before, edit.After})
+		}
+		if !reflect.DeepEqual(contexts, test.expected) {
+			t.Errorf("ContextualEdits(%q, %q, %d) = %v, expected %v", test.source, test.target, test.window, contexts, test.expected)
+		}
+	}
+}
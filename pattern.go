@@ -0,0 +1,71 @@
+package levenshtein
+
+import "sync"
+
+// Pattern is a pre-processed string which can be efficiently compared against
+// many target strings. It is safe for concurrent use by multiple goroutines.
+type Pattern struct {
+	config Matrix
+	pool   sync.Pool
+}
+
+// Compile prepares a pattern for repeated comparisons against different
+// target strings, using the given options. Only the edit distance is
+// calculated when comparing against a pattern, so no edit matrix is retained -
+// instead, a pooled workspace of two matrix rows is reused across calls.
+func Compile(pattern string, options ...Option) *Pattern {
+	p := &Pattern{
+		config: *newConfig(options),
+	}
+	p.config.source = p.config.prepare(pattern)
+	return p
+}
+
+// Distance returns the edit distance between the pattern and the target
+// string. It is equivalent to Distance(pattern, target, options...).
+func (p *Pattern) Distance(target string) int {
+	dist, _ := p.distance(p.config.prepare(target), -1)
+	return dist
+}
+
+// Within reports whether the edit distance between the pattern and the target
+// string is at most max. It stops as soon as it is clear that the distance
+// exceeds max, which makes it cheaper than Distance for dissimilar strings.
+// Distances are not negative unless WordBoundaryBonus is used, so otherwise,
+// it always returns false for a negative max.
+func (p *Pattern) Within(target string, max int) bool {
+	if max < 0 {
+		if p.config.bonus == 0 {
+			return false
+		}
+		// A negative max would never give up, so compare the distance
+		// with max directly
+		dist, ok := p.distance(p.config.prepare(target), -1)
+		return ok && dist <= max
+	}
+	_, ok := p.distance(p.config.prepare(target), max)
+	return ok
+}
+
+// distance calculates the edit distance between the pattern and the
+// (prepared) target, giving up once the distance is known to be greater than
+// max. A negative max means the calculation never gives up.
+func (p *Pattern) distance(target []rune, max int) (int, bool) {
+	rows := getRows(&p.pool, len(target)+1)
+	defer p.pool.Put(rows)
+	return p.config.rowDistance(target, max, *rows)
+}
+
+// getRows returns a workspace from the pool large enough to hold two rows of
+// the given size.
+func getRows(pool *sync.Pool, size int) *[]int {
+	rows, _ := pool.Get().(*[]int)
+	if rows == nil {
+		rows = new([]int)
+	}
+	if cap(*rows) < 2*size {
+		*rows = make([]int, 2*size)
+	}
+	*rows = (*rows)[:2*size]
+	return rows
+}
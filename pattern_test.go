@@ -0,0 +1,101 @@
+package levenshtein_test
+
+import (
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+var patternTargets = []string{
+	"",
+	"horse",
+	"arose",
+	"hose",
+	"horses",
+	"ros",
+	"shore",
+	"completely different",
+}
+
+func TestPattern(t *testing.T) {
+	for _, pattern := range []string{"", "horse", "hörse"} {
+		for _, options := range [][]levenshtein.Option{
+			nil,
+			{levenshtein.SetSwapCost(3)},
+			{levenshtein.WordBoundaryBonus(2)},
+		} {
+			p := levenshtein.Compile(pattern, options...)
+			for _, target := range patternTargets {
+				want := levenshtein.Distance(pattern, target, options...)
+				if got := p.Distance(target); got != want {
+					t.Errorf("Compile(%q).Distance(%q) = %d, want %d", pattern, target, got, want)
+				}
+				// No distance is within a negative max, unless the distance
+				// is itself negative
+				start := -2
+				if want < 0 {
+					start = want - 1
+				}
+				for max := start; max <= want+1; max++ {
+					if got := p.Within(target, max); got != (want <= max) {
+						t.Errorf("Compile(%q).Within(%q, %d) = %t, want %t", pattern, target, max, got, want <= max)
+					}
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkPattern(b *testing.B) {
+	p := levenshtein.Compile("horse")
+	for i := 0; i < b.N; i++ {
+		for _, target := range patternTargets {
+			p.Distance(target)
+		}
+	}
+}
+
+func BenchmarkPatternDistance(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, target := range patternTargets {
+			levenshtein.Distance("horse", target)
+		}
+	}
+}
+
+func TestFuzzyPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		target   string
+		expected int
+	}{
+		{"a[xy]c?", "axcz", 0},
+		{"a[xy]c?", "aycz", 0},
+		{"a[xy]c?", "azcz", 1},
+		{"a[xy]c?", "axc", 1},
+		{"a[xy]c?", "xaxcz", 1},
+		{"a[xy]c?", "", 4},
+		{"a[xy]c?", "abcdef", 3},
+		{"", "abc", 3},
+		{"???", "héé", 0},
+		{`\?\[`, "?[", 0},
+		{`\?`, "a", 1},
+		{`[\]a]b`, "]b", 0},
+		{"kitten", "sitting", 3},
+	}
+	for _, test := range tests {
+		p, err := levenshtein.CompilePattern(test.pattern)
+		if err != nil {
+			t.Fatalf("CompilePattern(%q) returned error: %v", test.pattern, err)
+		}
+		if dist := p.Distance(test.target); dist != test.expected {
+			t.Errorf("CompilePattern(%q).Distance(%q) = %d, expected %d", test.pattern, test.target, dist, test.expected)
+		}
+	}
+
+	for _, pattern := range []string{"a[bc", "a[]", `ab\`, `[a\]`} {
+		if _, err := levenshtein.CompilePattern(pattern); err == nil {
+			t.Errorf("CompilePattern(%q) returned no error", pattern)
+		}
+	}
+}
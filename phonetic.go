@@ -0,0 +1,86 @@
+package levenshtein
+
+// PhoneticMaxCost is the highest cost returned by a PhoneticCost function:
+// the cost of swapping phonemes which have no features in common, or which
+// are missing from the feature table.
+const PhoneticMaxCost = 4
+
+// DefaultPhoneticFeatures is a small table of the phonetic features of common
+// phonemes, written as IPA symbols. Consonants are described by their class,
+// voicing, place of articulation, and manner of articulation, while vowels
+// are described by their class, height, backness, and rounding.
+var DefaultPhoneticFeatures = map[rune][]string{
+	// Consonants
+	'p': {"consonant", "voiceless", "bilabial", "stop"},
+	'b': {"consonant", "voiced", "bilabial", "stop"},
+	't': {"consonant", "voiceless", "alveolar", "stop"},
+	'd': {"consonant", "voiced", "alveolar", "stop"},
+	'k': {"consonant", "voiceless", "velar", "stop"},
+	'g': {"consonant", "voiced", "velar", "stop"},
+	'f': {"consonant", "voiceless", "labiodental", "fricative"},
+	'v': {"consonant", "voiced", "labiodental", "fricative"},
+	'θ': {"consonant", "voiceless", "dental", "fricative"},
+	'ð': {"consonant", "voiced", "dental", "fricative"},
+	's': {"consonant", "voiceless", "alveolar", "fricative"},
+	'z': {"consonant", "voiced", "alveolar", "fricative"},
+	'ʃ': {"consonant", "voiceless", "postalveolar", "fricative"},
+	'ʒ': {"consonant", "voiced", "postalveolar", "fricative"},
+	'h': {"consonant", "voiceless", "glottal", "fricative"},
+	'm': {"consonant", "voiced", "bilabial", "nasal"},
+	'n': {"consonant", "voiced", "alveolar", "nasal"},
+	'ŋ': {"consonant", "voiced", "velar", "nasal"},
+	'l': {"consonant", "voiced", "alveolar", "lateral"},
+	'r': {"consonant", "voiced", "alveolar", "approximant"},
+	'j': {"consonant", "voiced", "palatal", "approximant"},
+	'w': {"consonant", "voiced", "labiovelar", "approximant"},
+
+	// Vowels
+	'i': {"vowel", "close", "front", "unrounded"},
+	'ɪ': {"vowel", "near-close", "front", "unrounded"},
+	'e': {"vowel", "close-mid", "front", "unrounded"},
+	'ɛ': {"vowel", "open-mid", "front", "unrounded"},
+	'æ': {"vowel", "near-open", "front", "unrounded"},
+	'a': {"vowel", "open", "front", "unrounded"},
+	'ə': {"vowel", "mid", "central", "unrounded"},
+	'ɑ': {"vowel", "open", "back", "unrounded"},
+	'ɔ': {"vowel", "open-mid", "back", "rounded"},
+	'o': {"vowel", "close-mid", "back", "rounded"},
+	'ʊ': {"vowel", "near-close", "back", "rounded"},
+	'u': {"vowel", "close", "back", "rounded"},
+}
+
+// PhoneticCost returns a swap cost function, intended to be used with the
+// SetSwapCostFunc option, under which the cost of swapping one phoneme for
+// another is the number of phonetic features in which they differ, according
+// to the given feature table - so swapping similar sounds (e.g. 'b' and 'p',
+// which differ only in voicing) is cheap. Each phoneme in the table should
+// have PhoneticMaxCost features, listed in the same order. If the table is
+// nil, DefaultPhoneticFeatures is used. Swapping a character which is not in
+// the table costs PhoneticMaxCost, so this function is best combined with
+// insert/remove costs of PhoneticMaxCost as well.
+func PhoneticCost(table map[rune][]string) func(from, to rune) int {
+	if table == nil {
+		table = DefaultPhoneticFeatures
+	}
+	return func(from, to rune) int {
+		fromFeatures, ok := table[from]
+		if !ok {
+			return PhoneticMaxCost
+		}
+		toFeatures, ok := table[to]
+		if !ok {
+			return PhoneticMaxCost
+		}
+
+		cost := PhoneticMaxCost
+		for i := 0; i < len(fromFeatures) && i < len(toFeatures); i++ {
+			if fromFeatures[i] == toFeatures[i] {
+				cost--
+			}
+		}
+		if cost < 0 {
+			return 0
+		}
+		return cost
+	}
+}
@@ -0,0 +1,84 @@
+package levenshtein
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Costs returned by QWERTYSwapCost.
+const (
+	QWERTYAdjacentCost = 1
+	QWERTYDefaultCost  = 2
+)
+
+// qwertyAdjacency maps each key on a standard US QWERTY keyboard to the keys
+// which physically border it (including diagonally across rows).
+var qwertyAdjacency = map[rune]string{
+	'`':  "1",
+	'1':  "`2q",
+	'2':  "13qw",
+	'3':  "24we",
+	'4':  "35er",
+	'5':  "46rt",
+	'6':  "57ty",
+	'7':  "68yu",
+	'8':  "79ui",
+	'9':  "80io",
+	'0':  "9-op",
+	'-':  "0=p[",
+	'=':  "-[]",
+	'q':  "12wa",
+	'w':  "23qeas",
+	'e':  "34wrsd",
+	'r':  "45etdf",
+	't':  "56ryfg",
+	'y':  "67tugh",
+	'u':  "78yihj",
+	'i':  "89uojk",
+	'o':  "90ipkl",
+	'p':  "0-o[l;",
+	'[':  "-=p];'",
+	']':  "=[\\'",
+	'\\': "]",
+	'a':  "qwsz",
+	's':  "weadzx",
+	'd':  "ersfxc",
+	'f':  "rtdgcv",
+	'g':  "tyfhvb",
+	'h':  "yugjbn",
+	'j':  "uihknm",
+	'k':  "iojlm,",
+	'l':  "opk;,.",
+	';':  "p[l'./",
+	'\'': "[];/",
+	'z':  "asx",
+	'x':  "sdzc",
+	'c':  "dfxv",
+	'v':  "fgcb",
+	'b':  "ghvn",
+	'n':  "hjbm",
+	'm':  "jkn,",
+	',':  "klm.",
+	'.':  "l;,/",
+	'/':  ";'.",
+}
+
+// QWERTYSwapCost is a swap cost function, intended to be used with the
+// SetSwapCostFunc option, which models typos caused by hitting a neighbouring
+// key on a standard US QWERTY keyboard. Swapping a character for one on a
+// physically adjacent key costs QWERTYAdjacentCost, while any other swap costs
+// QWERTYDefaultCost. Letters are compared case-insensitively, so swapping a
+// letter for the same letter in a different case (i.e. the same key, with or
+// without shift) also costs QWERTYAdjacentCost, as a near miss rather than a
+// free match. Use CaseChangeCost to give case changes a different cost. Keys
+// which are not in the table (e.g. shifted symbols, or non-ASCII characters)
+// are never adjacent to any other key. Because the adjacent cost must be lower
+// than the default, the default is twice the usual swap cost, so this function
+// is best combined with insert/remove costs of QWERTYDefaultCost as well.
+func QWERTYSwapCost(from, to rune) int {
+	from, to = unicode.ToLower(from), unicode.ToLower(to)
+	if from == to || strings.ContainsRune(qwertyAdjacency[from], to) {
+		return QWERTYAdjacentCost
+	}
+	return QWERTYDefaultCost
+}
@@ -0,0 +1,57 @@
+package levenshtein_test
+
+import (
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func TestQWERTYSwapCost(t *testing.T) {
+	const (
+		adjacent = levenshtein.QWERTYAdjacentCost
+		other    = levenshtein.QWERTYDefaultCost
+	)
+	for _, test := range []struct {
+		from, to rune
+		expected int
+	}{
+		// Adjacent keys, in the same row and across rows
+		{'p', 'o', adjacent},
+		{'o', 'p', adjacent},
+		{'g', 'h', adjacent},
+		{'w', 's', adjacent},
+		{'b', 'g', adjacent},
+		{'1', 'q', adjacent},
+		{';', '/', adjacent},
+		// Non-adjacent keys
+		{'p', 'x', other},
+		{'a', 'l', other},
+		{'q', 'p', other},
+		{'1', '0', other},
+		// Case changes, and adjacency regardless of case
+		{'A', 'a', adjacent},
+		{'a', 'A', adjacent},
+		{'P', 'o', adjacent},
+		{'p', 'O', adjacent},
+		{'P', 'X', other},
+		// Characters which are not in the table
+		{'!', '1', other},
+		{' ', 'a', other},
+		{'é', 'e', other},
+		{'é', 'É', adjacent},
+	} {
+		if cost := levenshtein.QWERTYSwapCost(test.from, test.to); cost != test.expected {
+			t.Errorf("QWERTYSwapCost(%q, %q) = %d, expected %d", test.from, test.to, cost, test.expected)
+		}
+	}
+
+	// A typo on a neighbouring key is closer than one on a distant key
+	options := []levenshtein.Option{
+		levenshtein.SetInsertCost(levenshtein.QWERTYDefaultCost),
+		levenshtein.SetRemoveCost(levenshtein.QWERTYDefaultCost),
+		levenshtein.SetSwapCostFunc(levenshtein.QWERTYSwapCost),
+	}
+	if near, far := levenshtein.Distance("hellp", "hello", options...), levenshtein.Distance("hellx", "hello", options...); near >= far {
+		t.Errorf("Distance(\"hellp\", \"hello\") = %d, expected less than Distance(\"hellx\", \"hello\") = %d", near, far)
+	}
+}
@@ -0,0 +1,66 @@
+package levenshtein
+
+// RollingComparator compares each string it is given against the last few
+// strings it was given, e.g. to collapse near-duplicate log lines. It is not
+// safe for concurrent use.
+type RollingComparator struct {
+	config *Matrix
+	lines  []string
+	runes  [][]rune
+	next   int // Index in the ring buffer at which the next line is stored
+	rows   []int
+}
+
+// NewRollingComparator returns a RollingComparator which compares each line
+// against the previous k lines, using the given options. If k is 0, there are
+// never any previous lines to compare against. It panics if k is negative.
+func NewRollingComparator(k int, options ...Option) *RollingComparator {
+	return &RollingComparator{
+		config: newConfig(options),
+		lines:  make([]string, 0, k),
+		runes:  make([][]rune, 0, k),
+	}
+}
+
+// Add compares the line against the previous k lines, returning the minimum
+// edit distance and the line which achieved it (the most recent one, in the
+// case of a tie), and then adds it to the comparator, evicting the oldest
+// line if there are already k. Each comparison gives up as soon as it is
+// clear that it cannot improve on the best distance found so far. If there
+// are no previous lines, minDist is -1.
+func (r *RollingComparator) Add(line string) (minDist int, closest string) {
+	m := *r.config
+	m.source = m.prepare(line)
+
+	found := false
+	for n := range r.lines {
+		// Compare against the most recent lines first
+		idx := (r.next - 1 - n + 2*len(r.lines)) % len(r.lines)
+		target := r.runes[idx]
+
+		if size := 2 * (len(target) + 1); len(r.rows) < size {
+			r.rows = make([]int, size)
+		}
+		if dist, ok := m.improve(target, minDist, found, r.rows); ok {
+			minDist, closest, found = dist, r.lines[idx], true
+		}
+		if found && m.unbeatable(minDist) {
+			break
+		}
+	}
+	if !found {
+		minDist = -1
+	}
+
+	if len(r.lines) < cap(r.lines) {
+		r.lines = append(r.lines, line)
+		r.runes = append(r.runes, m.source)
+	} else if len(r.lines) > 0 {
+		r.lines[r.next] = line
+		r.runes[r.next] = m.source
+	}
+	if len(r.lines) > 0 {
+		r.next = (r.next + 1) % cap(r.lines)
+	}
+	return minDist, closest
+}
@@ -0,0 +1,549 @@
+package levenshtein
+
+import (
+	"container/heap"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// DistanceToSet returns the minimum edit distance between the source string
+// and any of the alternatives - e.g. a list of synonyms. Once a candidate
+// distance has been found, each remaining alternative is only compared until
+// it is clear that it cannot improve on it. Returns -1 if there are no
+// alternatives.
+func DistanceToSet(source string, alternatives []string, options ...Option) int {
+	m := newConfig(options)
+	m.source = m.prepare(source)
+	var rows []int
+	best, found := 0, false
+	for _, alt := range alternatives {
+		target := m.prepare(alt)
+		if size := 2 * (len(target) + 1); cap(rows) < size {
+			rows = make([]int, size)
+		}
+		if dist, ok := m.improve(target, best, found, rows); ok {
+			best, found = dist, true
+		}
+		if found && m.unbeatable(best) {
+			break
+		}
+	}
+	if !found {
+		return -1
+	}
+	return best
+}
+
+// BestBase returns the index of the base string which is closest to the
+// target (the first one, if several are equally close), along with the
+// minimal list of edit operations which turns it into the target - e.g. for
+// applying a revision as a diff against whichever of several candidate base
+// revisions it is closest to. Once a candidate distance has been found, each
+// remaining base is only compared until it is clear that it cannot improve on
+// it. Returns -1 and no operations if there are no bases.
+func BestBase(target string, bases []string, options ...Option) (index int, ops []Operation) {
+	if len(bases) == 0 {
+		return -1, nil
+	}
+
+	config := newConfig(options)
+	t := config.prepare(target)
+	rows := make([]int, 2*(len(t)+1))
+	m := *config
+	best, found := 0, false
+	for i, base := range bases {
+		m.source = config.prepare(base)
+		if dist, ok := m.improve(t, best, found, rows); ok {
+			best, found, index = dist, true, i
+		}
+		if found && m.unbeatable(best) {
+			break
+		}
+	}
+	return index, Build(bases[index], target, options...).Operations()
+}
+
+// AdjacentDistances returns the edit distance between each consecutive pair of
+// strings in the list - e.g. successive versions of a document - so the
+// result has one fewer element than the list (or is empty, if the list has
+// fewer than two elements). Each string is only prepared once, and a single
+// workspace is reused for all of the pairs.
+func AdjacentDistances(versions []string, options ...Option) []int {
+	dists := []int{}
+	if len(versions) < 2 {
+		return dists
+	}
+
+	config := newConfig(options)
+	var rows []int
+	prev := config.prepare(versions[0])
+	for _, version := range versions[1:] {
+		next := config.prepare(version)
+		if size := 2 * (len(next) + 1); cap(rows) < size {
+			rows = make([]int, size)
+		}
+		m := *config
+		m.source = prev
+		dist, _ := m.rowDistance(next, -1, rows)
+		dists = append(dists, dist)
+		prev = next
+	}
+	return dists
+}
+
+// MedianString returns the candidate with the minimum total edit distance to
+// all of the other candidates (the set median, which approximates the
+// generalized median string of the set), along with that total. Ties go to the
+// earliest candidate. If the costs are symmetric (see Matrix.Symmetric), each
+// pair is only compared once. The pairwise distances are calculated by a pool
+// of workers, one per CPU. Returns "" and 0 if there are no candidates.
+func MedianString(candidates []string, options ...Option) (string, int) {
+	if len(candidates) == 0 {
+		return "", 0
+	}
+
+	config := newConfig(options)
+	prepared := make([][]rune, len(candidates))
+	for i, candidate := range candidates {
+		prepared[i] = config.prepare(candidate)
+	}
+	symmetric := config.Symmetric()
+
+	// Each worker accumulates its own totals, which are summed at the end
+	rowsCh := make(chan int)
+	workers := runtime.GOMAXPROCS(0)
+	allTotals := make([][]int, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		totals := make([]int, len(candidates))
+		allTotals[w] = totals
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var rows []int
+			for i := range rowsCh {
+				m := *config
+				m.source = prepared[i]
+				for j, target := range prepared {
+					if j == i || (symmetric && j < i) {
+						continue
+					}
+					if size := 2 * (len(target) + 1); cap(rows) < size {
+						rows = make([]int, size)
+					}
+					dist, _ := m.rowDistance(target, -1, rows)
+					totals[i] += dist
+					if symmetric {
+						totals[j] += dist
+					}
+				}
+			}
+		}()
+	}
+	for i := range candidates {
+		rowsCh <- i
+	}
+	close(rowsCh)
+	wg.Wait()
+
+	best, bestTotal := 0, -1
+	for i := range candidates {
+		total := 0
+		for _, totals := range allTotals {
+			total += totals[i]
+		}
+		if bestTotal < 0 || total < bestTotal {
+			best, bestTotal = i, total
+		}
+	}
+	return candidates[best], bestTotal
+}
+
+// CircularDistance returns the minimum edit distance between any rotation of
+// the source string and the target string, for comparing cyclic sequences -
+// e.g. "abcde" and "cdeab" are at distance 0. Each distinct rotation of the
+// source is compared separately, so this takes O(n²m) time for a source of
+// length n and a target of length m, although there are only as many distinct
+// rotations as the period of the source, and each comparison stops once it is
+// clear that it cannot improve on the best distance found so far.
+func CircularDistance(source, target string, options ...Option) int {
+	config := newConfig(options)
+	s := config.prepare(source)
+	t := config.prepare(target)
+
+	// Every rotation is a window of the source repeated twice
+	doubled := append(append(make([]rune, 0, 2*len(s)), s...), s...)
+	rows := make([]int, 2*(len(t)+1))
+	m := *config
+	best, found := 0, false
+	for k := 0; k < period(s); k++ {
+		m.source = doubled[k : k+len(s)]
+		if dist, ok := m.improve(t, best, found, rows); ok {
+			best, found = dist, true
+		}
+		if found && m.unbeatable(best) {
+			break
+		}
+	}
+	if !found {
+		// The source is empty, so it has only one rotation (or no rotation
+		// is within the limits on the number of operations)
+		m.source = s
+		best, _ = m.rowDistance(t, -1, rows)
+	}
+	return best
+}
+
+// period returns the length of the shortest prefix of the runes which can be
+// repeated to produce them (or the length of the runes, if there is no
+// shorter one), using the failure function of the Knuth-Morris-Pratt
+// algorithm.
+func period(runes []rune) int {
+	if len(runes) == 0 {
+		return 0
+	}
+	fail := make([]int, len(runes))
+	for i, k := 1, 0; i < len(runes); i++ {
+		for k > 0 && runes[i] != runes[k] {
+			k = fail[k-1]
+		}
+		if runes[i] == runes[k] {
+			k++
+		}
+		fail[i] = k
+	}
+	if p := len(runes) - fail[len(runes)-1]; len(runes)%p == 0 {
+		return p
+	}
+	return len(runes)
+}
+
+// Dedup removes near-duplicates from a list of strings, returning a list in
+// which no two strings are within maxDist of each other. It makes a single
+// greedy pass over the list, keeping each string unless it is within maxDist
+// of a string which has already been kept, so the first occurrence of each
+// group of near-duplicates is the one which is kept. If maxDist is negative,
+// no strings are near-duplicates, and a copy of the list is returned.
+func Dedup(strs []string, maxDist int, options ...Option) []string {
+	result := []string{}
+	if maxDist < 0 {
+		return append(result, strs...)
+	}
+	kept := []*Pattern{}
+	for _, s := range strs {
+		p := Compile(s, options...)
+		duplicate := false
+		for _, k := range kept {
+			if _, ok := k.distance(p.config.source, maxDist); ok {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			kept = append(kept, p)
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// ClusterByDistance groups the strings into clusters of near-duplicates, in
+// which each string is within maxDist of at least one other member of its
+// cluster, so that the members are transitively within maxDist of each other
+// (single-linkage clustering). Each cluster is a list of indices into strs, in
+// ascending order, and the clusters are ordered by their first index. Strings
+// which are not within maxDist of any other string form clusters of their own.
+// If the costs are not symmetric (see Matrix.Symmetric), two strings are
+// linked if either is within maxDist of the other. Pairs which are already in
+// the same cluster are not compared, and each remaining comparison stops as
+// soon as it is clear that the pair is not within maxDist.
+func ClusterByDistance(strs []string, maxDist int, options ...Option) [][]int {
+	config := newConfig(options)
+	prepared := prepareAll(strs, options)
+	symmetric := config.Symmetric()
+
+	// Union-find over the indices, where each root is the smallest index
+	// of its cluster
+	parent := make([]int, len(strs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i]) // Path compression
+		}
+		return parent[i]
+	}
+	var rows []int
+	within := func(source, target []rune) bool {
+		m := *config
+		m.source = source
+		if m.lengthCost(len(source), len(target)) > maxDist {
+			return false
+		}
+		if size := 2 * (len(target) + 1); cap(rows) < size {
+			rows = make([]int, size)
+		}
+		_, ok := m.rowDistance(target, maxDist, rows)
+		return ok
+	}
+
+	if maxDist >= 0 {
+		for i := range prepared {
+			for j := i + 1; j < len(prepared); j++ {
+				rootI, rootJ := find(i), find(j)
+				if rootI == rootJ {
+					continue
+				}
+				if within(prepared[i], prepared[j]) || (!symmetric && within(prepared[j], prepared[i])) {
+					if rootI < rootJ {
+						parent[rootJ] = rootI
+					} else {
+						parent[rootI] = rootJ
+					}
+				}
+			}
+		}
+	}
+
+	clusters := [][]int{}
+	clusterOf := map[int]int{} // Index of each root's cluster
+	for i := range strs {
+		root := find(i)
+		k, ok := clusterOf[root]
+		if !ok {
+			k = len(clusters)
+			clusterOf[root] = k
+			clusters = append(clusters, nil)
+		}
+		clusters[k] = append(clusters[k], i)
+	}
+	return clusters
+}
+
+// Match is a candidate string which matched a query, along with its index in
+// the list of candidates, its edit distance from the query, and its
+// similarity ratio (see Score).
+type Match struct {
+	Candidate string
+	Index     int
+	Distance  int
+	Ratio     float64
+}
+
+// ratioEpsilon is the tolerance used when comparing similarity ratios, to
+// allow for floating point rounding.
+const ratioEpsilon = 1e-9
+
+// FilterByRatio returns the candidates whose similarity ratio with the query
+// (see Score) is at least minRatio, sorted by descending ratio, with ties in
+// their original order. The ratio threshold is converted into an equivalent
+// maximum distance for each candidate, so that each comparison can stop as
+// soon as it is clear that the candidate does not meet the threshold. Since
+// ratios are floored at 0, every candidate meets a minRatio of 0 or less, so
+// the comparisons are not stopped early in that case.
+func FilterByRatio(query string, candidates []string, minRatio float64, options ...Option) []Match {
+	p := Compile(query, options...)
+	matches := []Match{}
+	for i, candidate := range candidates {
+		target := p.config.prepare(candidate)
+		length := len(target)
+		if len(p.config.source) > length {
+			length = len(p.config.source)
+		}
+
+		maxDist := -1
+		if minRatio > 0 {
+			maxDist = int((1-minRatio)*float64(length) + ratioEpsilon)
+			if maxDist < 0 {
+				continue
+			}
+		}
+		dist, ok := p.distance(target, maxDist)
+		if !ok {
+			continue
+		}
+		if ratio := similarityRatio(dist, len(p.config.source), len(target)); ratio+ratioEpsilon >= minRatio {
+			matches = append(matches, Match{
+				Candidate: candidate,
+				Index:     i,
+				Distance:  dist,
+				Ratio:     ratio,
+			})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Ratio > matches[j].Ratio
+	})
+	return matches
+}
+
+// StreamTopK returns the k candidates received from the channel which are
+// closest to the query, sorted by ascending distance, with ties in the order
+// in which they were received. The channel is read until it is closed. Only
+// the best k matches seen so far are held in memory, and once k matches have
+// been found, each remaining candidate is only compared until it is clear that
+// it cannot improve on the worst of them. The Index of each match is the
+// position of the candidate in the stream.
+func StreamTopK(query string, candidates <-chan string, k int, options ...Option) []Match {
+	p := Compile(query, options...)
+	best := &matchHeap{}
+	var rows []int
+	i := 0
+	for candidate := range candidates {
+		index := i
+		i++
+		if k <= 0 {
+			continue
+		}
+
+		target := p.config.prepare(candidate)
+		full := best.Len() == k
+		if full && p.config.unbeatable((*best)[0].Distance) {
+			continue // No candidate can improve on the worst match
+		}
+		if size := 2 * (len(target) + 1); cap(rows) < size {
+			rows = make([]int, size)
+		}
+		worst := 0
+		if full {
+			worst = (*best)[0].Distance
+		}
+		dist, ok := p.config.improve(target, worst, full, rows)
+		if !ok {
+			continue
+		}
+
+		match := Match{
+			Candidate: candidate,
+			Index:     index,
+			Distance:  dist,
+			Ratio:     similarityRatio(dist, len(p.config.source), len(target)),
+		}
+		if best.Len() == k {
+			(*best)[0] = match
+			heap.Fix(best, 0)
+		} else {
+			heap.Push(best, match)
+		}
+	}
+
+	matches := []Match(*best)
+	sort.Slice(matches, func(i, j int) bool {
+		return matchHeap(matches).Less(j, i)
+	})
+	return matches
+}
+
+// matchHeap is a heap of matches, with the worst match (i.e. the one with the
+// greatest distance, or the latest index among equal distances) at the root.
+type matchHeap []Match
+
+func (h matchHeap) Len() int { return len(h) }
+
+func (h matchHeap) Less(i, j int) bool {
+	if h[i].Distance != h[j].Distance {
+		return h[i].Distance > h[j].Distance
+	}
+	return h[i].Index > h[j].Index
+}
+
+func (h matchHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *matchHeap) Push(x any) { *h = append(*h, x.(Match)) }
+
+func (h *matchHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// WithinDistance returns every candidate within edit distance k of the query,
+// sorted by ascending distance, with ties in their original order. Unlike
+// StreamTopK, it returns all of the qualifying candidates, however many there
+// are. Candidates whose difference in length from the query alone rules them
+// out are skipped without being compared, and each remaining comparison stops
+// as soon as it is clear that the candidate is not within k.
+func WithinDistance(query string, candidates []string, k int, options ...Option) []Match {
+	p := Compile(query, options...)
+	matches := []Match{}
+	if k < 0 {
+		return matches
+	}
+	for i, candidate := range candidates {
+		target := p.config.prepare(candidate)
+		if p.config.lengthCost(len(p.config.source), len(target)) > k {
+			continue
+		}
+		dist, ok := p.distance(target, k)
+		if !ok {
+			continue
+		}
+		matches = append(matches, Match{
+			Candidate: candidate,
+			Index:     i,
+			Distance:  dist,
+			Ratio:     similarityRatio(dist, len(p.config.source), len(target)),
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Distance < matches[j].Distance
+	})
+	return matches
+}
+
+// lengthCost returns a lower bound on the distance between strings of the
+// given lengths, based on the insertions or removals needed to make up the
+// difference in length. It returns zero if the costs are such that no bound
+// can be determined cheaply - including if any cost is negative (or may be,
+// in the case of a swap cost function), since the other operations could then
+// make up for the cost of the insertions or removals.
+func (m *Matrix) lengthCost(sourceLen, targetLen int) int {
+	if m.affine || m.costs != nil || m.confusions != nil || m.weights != nil || m.firstCost < 1 || m.bonus != 0 {
+		return 0
+	}
+	if m.insertCost < 0 || m.removeCost < 0 || m.swapCost < 0 || (m.markCostOK && m.markCost < 0) || (m.caseCostOK && m.caseCost < 0) || m.swapFunc != nil {
+		return 0
+	}
+	diff, cost := targetLen-sourceLen, m.insertCost
+	if diff < 0 {
+		diff, cost = -diff, m.removeCost
+	}
+	if m.markCostOK && m.markCost < cost {
+		cost = m.markCost
+	}
+	if cost <= 0 {
+		return 0
+	}
+	return diff * cost
+}
+
+// SortStructsByDistance sorts the items in place by ascending edit distance
+// between the query and the string returned by key for each item, with ties
+// in their original order - e.g. for ranking search results by the
+// similarity of their names to the query. The key function is called exactly
+// once per item, and each distance is calculated once and cached for the
+// duration of the sort, rather than being recalculated for every comparison.
+func SortStructsByDistance[T any](items []T, query string, key func(T) string, options ...Option) {
+	p := Compile(query, options...)
+	ranked := make([]struct {
+		item T
+		dist int
+	}, len(items))
+	for i, item := range items {
+		ranked[i].item = item
+		ranked[i].dist = p.Distance(key(item))
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].dist < ranked[j].dist
+	})
+	for i := range ranked {
+		items[i] = ranked[i].item
+	}
+}
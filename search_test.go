@@ -0,0 +1,551 @@
+package levenshtein_test
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+func TestStreamTopK(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	candidates := make([]string, 500)
+	for i := range candidates {
+		b := make([]byte, 3+rnd.Intn(6))
+		for j := range b {
+			b[j] = "abcde"[rnd.Intn(5)]
+		}
+		candidates[i] = string(b)
+	}
+	query := "abcde"
+
+	// Brute force: compute every distance and sort, keeping ties in order
+	all := make([]levenshtein.Match, len(candidates))
+	for i, candidate := range candidates {
+		all[i] = levenshtein.Match{
+			Candidate: candidate,
+			Index:     i,
+			Distance:  levenshtein.Distance(query, candidate),
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Distance < all[j].Distance
+	})
+
+	for _, k := range []int{0, 1, 5, 50, 1000} {
+		ch := make(chan string)
+		go func() {
+			defer close(ch)
+			for _, candidate := range candidates {
+				ch <- candidate
+			}
+		}()
+
+		matches := levenshtein.StreamTopK(query, ch, k)
+		expected := all
+		if k < len(expected) {
+			expected = expected[:k]
+		}
+		if len(matches) != len(expected) {
+			t.Fatalf("StreamTopK(k=%d) returned %d matches, expected %d", k, len(matches), len(expected))
+		}
+		for i := range matches {
+			matches[i].Ratio = 0
+		}
+		if len(expected) > 0 && !reflect.DeepEqual(matches, expected) {
+			t.Errorf("StreamTopK(k=%d) = %v, expected %v", k, matches, expected)
+		}
+	}
+}
+
+func TestAdjacentDistances(t *testing.T) {
+	versions := []string{
+		"The quick brown fox",
+		"The quick brown fox jumps",
+		"The quick brown fox jumps over the lazy dog",
+		"The quick brown fox jumped over the lazy dog",
+		"",
+		"The slow brown fox",
+		"The slow brown fox",
+	}
+	options := []levenshtein.Option{levenshtein.SetSwapCost(2), levenshtein.IgnoreCase()}
+
+	dists := levenshtein.AdjacentDistances(versions, options...)
+	if len(dists) != len(versions)-1 {
+		t.Fatalf("AdjacentDistances returned %d distances, expected %d", len(dists), len(versions)-1)
+	}
+	for i, dist := range dists {
+		if expected := levenshtein.Distance(versions[i], versions[i+1], options...); dist != expected {
+			t.Errorf("AdjacentDistances()[%d] = %d, expected %d", i, dist, expected)
+		}
+	}
+
+	for _, versions := range [][]string{nil, {"only"}} {
+		if dists := levenshtein.AdjacentDistances(versions); len(dists) != 0 {
+			t.Errorf("AdjacentDistances(%q) = %v, expected no distances", versions, dists)
+		}
+	}
+}
+
+// insertHeavyModel is a CostModel under which inserting a character costs
+// more than removing it.
+type insertHeavyModel struct{}
+
+func (insertHeavyModel) Insert(r rune) int      { return 3 }
+func (insertHeavyModel) Remove(r rune) int      { return 1 }
+func (insertHeavyModel) Swap(from, to rune) int { return 1 }
+func (insertHeavyModel) Match(r rune) int       { return 0 }
+
+func TestDistanceToSet(t *testing.T) {
+	for _, test := range []struct {
+		source       string
+		alternatives []string
+		options      []levenshtein.Option
+		dist         int
+	}{
+		{"cart", nil, nil, -1},
+		{"cart", []string{}, nil, -1},
+		{"cart", []string{"cat"}, nil, 1},
+		// Alternatives after the closest are cut off by the bound, whether
+		// they are further away or just as close
+		{"cart", []string{"car", "automobile", "carts"}, nil, 1},
+		{"cart", []string{"automobile", "wagon", "car"}, nil, 1},
+		{"cart", []string{"automobile", "cart", "car"}, nil, 0},
+		// Duplicates make no difference
+		{"cart", []string{"wagon", "wagon", "cat", "cat"}, nil, 1},
+		{"cart", []string{"", ""}, nil, 4},
+		// Alternatives which can't be reached within the limits are ignored
+		{"abc", []string{"ab", "abcd"}, []levenshtein.Option{levenshtein.MaxRemoves(0)}, 1},
+		{"abc", []string{"ab", "a"}, []levenshtein.Option{levenshtein.MaxRemoves(0)}, -1},
+	} {
+		if dist := levenshtein.DistanceToSet(test.source, test.alternatives, test.options...); dist != test.dist {
+			t.Errorf("DistanceToSet(%q, %q) = %d, expected %d", test.source, test.alternatives, dist, test.dist)
+		}
+
+		// The result is the minimum over the alternatives, compared fully
+		closest := -1
+		for _, alt := range test.alternatives {
+			if dist := levenshtein.Distance(test.source, alt, test.options...); dist >= 0 && (closest < 0 || dist < closest) {
+				closest = dist
+			}
+		}
+		if closest != test.dist {
+			t.Errorf("minimum Distance(%q, %q) = %d, expected %d", test.source, test.alternatives, closest, test.dist)
+		}
+	}
+}
+
+func TestRollingComparator(t *testing.T) {
+	type result struct {
+		dist    int
+		closest string
+	}
+	for _, test := range []struct {
+		k        int
+		lines    []string
+		expected []result
+	}{
+		{2, []string{"hello", "help", "hello"}, []result{{-1, ""}, {2, "hello"}, {0, "hello"}}},
+		// The closest line is the most recent one, in the case of a tie
+		{2, []string{"ab", "ac", "ad"}, []result{{-1, ""}, {1, "ab"}, {1, "ac"}}},
+		// The oldest line is evicted once there are k
+		{2, []string{"aaaa", "bbbb", "cccc", "aaaa", "bbbb"}, []result{{-1, ""}, {4, "aaaa"}, {4, "bbbb"}, {4, "cccc"}, {4, "aaaa"}}},
+		{1, []string{"abc", "abd", "abc"}, []result{{-1, ""}, {1, "abc"}, {1, "abd"}}},
+		{0, []string{"abc", "abc"}, []result{{-1, ""}, {-1, ""}}},
+	} {
+		r := levenshtein.NewRollingComparator(test.k)
+		for n, line := range test.lines {
+			dist, closest := r.Add(line)
+			if got := (result{dist, closest}); got != test.expected[n] {
+				t.Errorf("k=%d, %q: Add(%q) = %d, %q, expected %d, %q", test.k, test.lines[:n], line, dist, closest, test.expected[n].dist, test.expected[n].closest)
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewRollingComparator(-1) did not panic")
+		}
+	}()
+	levenshtein.NewRollingComparator(-1)
+}
+
+func TestFilterByRatio(t *testing.T) {
+	candidates := []string{"apple", "appel", "banana", "apply"}
+	var got []string
+	for _, match := range levenshtein.FilterByRatio("apple", candidates, 0.8) {
+		got = append(got, match.Candidate)
+	}
+	if expected := []string{"apple", "apply"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("FilterByRatio(0.8) = %q, expected %q", got, expected)
+	}
+
+	// Every candidate meets a ratio of 0, even if its distance is greater
+	// than the length of the longer string
+	for _, minRatio := range []float64{0, -1} {
+		got = nil
+		for _, match := range levenshtein.FilterByRatio("ab", []string{"cd", "ab"}, minRatio, levenshtein.SetSwapCost(5)) {
+			got = append(got, match.Candidate)
+		}
+		if expected := []string{"ab", "cd"}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("FilterByRatio(%v) = %q, expected %q", minRatio, got, expected)
+		}
+	}
+}
+
+func TestDedup(t *testing.T) {
+	strs := []string{"color", "colour", "flavor", "color", "flavour"}
+	for _, test := range []struct {
+		maxDist  int
+		expected []string
+	}{
+		{-1, []string{"color", "colour", "flavor", "color", "flavour"}},
+		{0, []string{"color", "colour", "flavor", "flavour"}},
+		{1, []string{"color", "flavor"}},
+	} {
+		if got := levenshtein.Dedup(strs, test.maxDist); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Dedup(%d) = %q, expected %q", test.maxDist, got, test.expected)
+		}
+	}
+
+	// The result is a copy, even if nothing is removed
+	got := levenshtein.Dedup(strs, -1)
+	got[0] = "changed"
+	if strs[0] != "color" {
+		t.Error("Dedup(-1) returned the input list rather than a copy")
+	}
+	if got := levenshtein.Dedup(nil, -1); got == nil || len(got) != 0 {
+		t.Errorf("Dedup(nil, -1) = %#v, expected an empty list", got)
+	}
+}
+
+func TestMedianString(t *testing.T) {
+	tests := []struct {
+		candidates []string
+		options    []levenshtein.Option
+		median     string
+		total      int
+	}{
+		{nil, nil, "", 0},
+		{[]string{"only"}, nil, "only", 0},
+		{[]string{"kitten", "sitting"}, nil, "kitten", 3}, // Ties go to the first candidate
+		{[]string{"abc", "abd", "abe", "xbc", "abcd"}, nil, "abc", 4},
+		{[]string{"ABC", "abd", "Abe"}, []levenshtein.Option{levenshtein.IgnoreCase()}, "ABC", 2},
+		{[]string{"", "a", "aa", "aaa"}, []levenshtein.Option{levenshtein.SetInsertCost(3)}, "aa", 6},
+		// A cost model is not symmetric, even if its flat costs are
+		{[]string{"", "a", "aa"}, []levenshtein.Option{levenshtein.UseCostModel(insertHeavyModel{})}, "aa", 3},
+	}
+	for _, test := range tests {
+		median, total := levenshtein.MedianString(test.candidates, test.options...)
+		if median != test.median || total != test.total {
+			t.Errorf("MedianString(%q) = %q, %d, expected %q, %d", test.candidates, median, total, test.median, test.total)
+		}
+
+		// Verify against the distances calculated directly
+		for _, candidate := range test.candidates {
+			sum := 0
+			for _, other := range test.candidates {
+				sum += levenshtein.Distance(candidate, other, test.options...)
+			}
+			if sum < total {
+				t.Errorf("MedianString(%q): %q has total %d, less than median total %d", test.candidates, candidate, sum, total)
+			}
+		}
+	}
+}
+
+func TestCircularDistance(t *testing.T) {
+	rotate := func(s string, k int) string {
+		r := []rune(s)
+		return string(append(r[k:], r[:k]...))
+	}
+
+	for _, pair := range [][2]string{
+		{"", ""},
+		{"", "abc"},
+		{"abc", ""},
+		{"abcde", "cdeab"},
+		{"abab", "baba"},
+		{"aaaa", "aaa"},
+		{"héllo", "lohél"},
+		{"horse", "arose"},
+		{"kitten", "sitting"},
+	} {
+		expected := levenshtein.Distance(pair[0], pair[1])
+		for k := 1; k < len([]rune(pair[0])); k++ {
+			if dist := levenshtein.Distance(rotate(pair[0], k), pair[1]); dist < expected {
+				expected = dist
+			}
+		}
+		if dist := levenshtein.CircularDistance(pair[0], pair[1]); dist != expected {
+			t.Errorf("CircularDistance(%q, %q) = %d, expected %d", pair[0], pair[1], dist, expected)
+		}
+	}
+}
+
+func TestWithinDistance(t *testing.T) {
+	dictionary := []string{
+		"apple", "apply", "ample", "maple", "applesauce", "app", "pale",
+		"apples", "grape", "a", "", "appeal", "aple", "papel",
+	}
+	tests := []struct {
+		query    string
+		options  []levenshtein.Option
+		expected []string
+	}{
+		{"apple", nil, []string{"apple", "apply", "ample", "apples", "aple", "maple", "app", "pale"}},
+		{"aple", nil, []string{"aple", "apple", "ample", "maple", "apply", "app", "pale", "apples"}},
+		{"xyz", nil, nil},
+		{"APPLE", []levenshtein.Option{levenshtein.IgnoreCase()}, []string{"apple", "apply", "ample", "apples", "aple", "maple", "app", "pale"}},
+		// Insertions are free, so the length difference doesn't rule anything out
+		{"aple", []levenshtein.Option{levenshtein.SetInsertCost(0)}, []string{"apple", "ample", "maple", "applesauce", "apples", "aple", "apply", "pale", "grape", "appeal", "papel", "app"}},
+	}
+	for _, test := range tests {
+		matches := levenshtein.WithinDistance(test.query, dictionary, 2, test.options...)
+
+		// Brute force: every candidate within distance 2, in order of distance
+		expected := []levenshtein.Match{}
+		for i, candidate := range dictionary {
+			if dist := levenshtein.Distance(test.query, candidate, test.options...); dist <= 2 {
+				expected = append(expected, levenshtein.Match{Candidate: candidate, Index: i, Distance: dist})
+			}
+		}
+		sort.SliceStable(expected, func(i, j int) bool {
+			return expected[i].Distance < expected[j].Distance
+		})
+
+		var candidates []string
+		for i := range matches {
+			candidates = append(candidates, matches[i].Candidate)
+			matches[i].Ratio = 0
+		}
+		if !reflect.DeepEqual(matches, expected) {
+			t.Errorf("WithinDistance(%q) = %v, expected %v", test.query, matches, expected)
+		}
+		if !reflect.DeepEqual(candidates, test.expected) {
+			t.Errorf("WithinDistance(%q) candidates = %q, expected %q", test.query, candidates, test.expected)
+		}
+	}
+
+	if matches := levenshtein.WithinDistance("apple", dictionary, -1); len(matches) != 0 {
+		t.Errorf("WithinDistance(k=-1) = %v, expected none", matches)
+	}
+}
+
+func TestBestBase(t *testing.T) {
+	tests := []struct {
+		target   string
+		bases    []string
+		options  []levenshtein.Option
+		expected int
+	}{
+		{"the quick brown fox", []string{"a slow red fox", "the quick brown dog", "the quick fox"}, nil, 1},
+		{"kitten", []string{"sitting", "kitten", "mitten"}, nil, 1},
+		{"kitten", []string{"mitten", "bitten"}, nil, 0}, // Ties go to the first base
+		{"abc", []string{"abc"}, nil, 0},
+		{"", []string{"xyz", "x", ""}, nil, 2},
+		// Removals are expensive, so the shorter base is closer
+		{"abcd", []string{"abcdef", "ab"}, []levenshtein.Option{levenshtein.SetRemoveCost(5)}, 1},
+	}
+	for _, test := range tests {
+		index, ops := levenshtein.BestBase(test.target, test.bases, test.options...)
+		if index != test.expected {
+			t.Errorf("BestBase(%q, %q) index = %d, expected %d", test.target, test.bases, index, test.expected)
+			continue
+		}
+		result, err := levenshtein.Apply(test.bases[index], ops)
+		if err != nil {
+			t.Errorf("BestBase(%q, %q): Apply: %v", test.target, test.bases, err)
+		} else if result != test.target {
+			t.Errorf("BestBase(%q, %q): Apply = %q, expected %q", test.target, test.bases, result, test.target)
+		}
+	}
+
+	if index, ops := levenshtein.BestBase("abc", nil); index != -1 || ops != nil {
+		t.Errorf("BestBase with no bases = %d, %v, expected -1, nil", index, ops)
+	}
+}
+
+func TestSortStructsByDistance(t *testing.T) {
+	type product struct {
+		ID   int
+		Name string
+	}
+	products := []product{
+		{1, "keyboard"},
+		{2, "mouse"},
+		{3, "monitor"},
+		{4, "mousepad"},
+		{5, "house"},
+		{6, "Mouse"},
+	}
+
+	calls := map[int]int{}
+	name := func(p product) string {
+		calls[p.ID]++
+		return p.Name
+	}
+	levenshtein.SortStructsByDistance(products, "mouse", name)
+
+	var ids []int
+	for _, p := range products {
+		ids = append(ids, p.ID)
+	}
+	// mouse (0), house and Mouse (1, in their original order), mousepad (3),
+	// monitor (5), keyboard (7)
+	if expected := []int{2, 5, 6, 4, 3, 1}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("SortStructsByDistance order = %v, expected %v", ids, expected)
+	}
+	for id, n := range calls {
+		if n != 1 {
+			t.Errorf("key called %d times for item %d, expected once", n, id)
+		}
+	}
+	if len(calls) != len(products) {
+		t.Errorf("key called for %d items, expected %d", len(calls), len(products))
+	}
+
+	// Options apply to the distances
+	levenshtein.SortStructsByDistance(products, "MOUSE", func(p product) string { return p.Name }, levenshtein.IgnoreCase())
+	if products[0].ID != 2 || products[1].ID != 6 {
+		t.Errorf("SortStructsByDistance with IgnoreCase = %v, expected mouse and Mouse first", products)
+	}
+
+	levenshtein.SortStructsByDistance([]product(nil), "mouse", name)
+}
+
+func TestFuzzyJoin(t *testing.T) {
+	left := []string{"jon smith", "jane doe", "bob jones", "jane doe", "alice"}
+	right := []string{"john smith", "jayne doe", "jane do", "xavier", "jon smyth"}
+
+	// Ties go to the earliest right string, which may be matched repeatedly
+	expected := []levenshtein.JoinPair{
+		{LeftIdx: 0, RightIdx: 0, Distance: 1},
+		{LeftIdx: 1, RightIdx: 1, Distance: 1},
+		{LeftIdx: 3, RightIdx: 1, Distance: 1},
+	}
+	if pairs := levenshtein.FuzzyJoin(left, right, 2); !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("FuzzyJoin() = %v, expected %v", pairs, expected)
+	}
+
+	// The closest match is found, even if an earlier one is within maxDist
+	expected = []levenshtein.JoinPair{
+		{LeftIdx: 0, RightIdx: 1, Distance: 0},
+	}
+	if pairs := levenshtein.FuzzyJoin([]string{"grape"}, []string{"grace", "grape"}, 2); !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("FuzzyJoin(grape) = %v, expected %v", pairs, expected)
+	}
+
+	// Each right string is only matched once
+	expected = []levenshtein.JoinPair{
+		{LeftIdx: 0, RightIdx: 0, Distance: 1},
+		{LeftIdx: 1, RightIdx: 1, Distance: 1},
+		{LeftIdx: 3, RightIdx: 2, Distance: 1},
+	}
+	if pairs := levenshtein.FuzzyJoinUnique(left, right, 2); !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("FuzzyJoinUnique() = %v, expected %v", pairs, expected)
+	}
+
+	// Closer pairs are assigned first
+	expected = []levenshtein.JoinPair{
+		{LeftIdx: 0, RightIdx: 1, Distance: 1},
+		{LeftIdx: 1, RightIdx: 0, Distance: 0},
+	}
+	if pairs := levenshtein.FuzzyJoinUnique([]string{"cat", "hat"}, []string{"hat", "cut"}, 1); !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("FuzzyJoinUnique(cat, hat) = %v, expected %v", pairs, expected)
+	}
+
+	for _, join := range []func([]string, []string, int, ...levenshtein.Option) []levenshtein.JoinPair{
+		levenshtein.FuzzyJoin,
+		levenshtein.FuzzyJoinUnique,
+	} {
+		if pairs := join(left, right, -1); len(pairs) != 0 {
+			t.Errorf("join with negative maxDist = %v, expected no pairs", pairs)
+		}
+		if pairs := join(nil, right, 2); len(pairs) != 0 {
+			t.Errorf("join with no left strings = %v, expected no pairs", pairs)
+		}
+		if pairs := join([]string{"JANE DOE"}, right, 1, levenshtein.IgnoreCase()); len(pairs) != 1 || pairs[0].RightIdx != 1 {
+			t.Errorf("join with IgnoreCase = %v, expected a match with jayne doe", pairs)
+		}
+	}
+}
+
+func TestClusterByDistance(t *testing.T) {
+	strs := []string{"apple", "banana", "appel", "bananas", "aple", "cherry", "bandana"}
+	tests := []struct {
+		maxDist  int
+		options  []levenshtein.Option
+		expected [][]int
+	}{
+		// "bandana" is linked to "bananas" via "banana"
+		{1, nil, [][]int{{0, 4}, {1, 3, 6}, {2}, {5}}},
+		// "appel" is at distance 2 from both "apple" and "aple"
+		{2, nil, [][]int{{0, 2, 4}, {1, 3, 6}, {5}}},
+		{0, nil, [][]int{{0}, {1}, {2}, {3}, {4}, {5}, {6}}},
+		{-1, nil, [][]int{{0}, {1}, {2}, {3}, {4}, {5}, {6}}},
+		// Under asymmetric costs, a pair is linked if either direction is
+		// within maxDist
+		{1, []levenshtein.Option{levenshtein.SetInsertCost(5)}, [][]int{{0, 4}, {1, 3, 6}, {2}, {5}}},
+		{1, []levenshtein.Option{levenshtein.UseCostModel(insertHeavyModel{})}, [][]int{{0, 4}, {1, 3, 6}, {2}, {5}}},
+	}
+	for _, test := range tests {
+		clusters := levenshtein.ClusterByDistance(strs, test.maxDist, test.options...)
+		if !reflect.DeepEqual(clusters, test.expected) {
+			t.Errorf("ClusterByDistance(%d) = %v, expected %v", test.maxDist, clusters, test.expected)
+		}
+	}
+
+	// Clusters are linked transitively, even when the link is found after
+	// both members have joined other clusters
+	clusters := levenshtein.ClusterByDistance([]string{"aaaa", "zzzz", "aazz", "aaaz", "azzz"}, 1)
+	if expected := [][]int{{0, 1, 2, 3, 4}}; !reflect.DeepEqual(clusters, expected) {
+		t.Errorf("ClusterByDistance(chain) = %v, expected %v", clusters, expected)
+	}
+
+	// Under a cost model, "" is only within 1 of "a" in one direction
+	clusters = levenshtein.ClusterByDistance([]string{"", "a"}, 1, levenshtein.UseCostModel(insertHeavyModel{}))
+	if expected := [][]int{{0, 1}}; !reflect.DeepEqual(clusters, expected) {
+		t.Errorf("ClusterByDistance(cost model) = %v, expected %v", clusters, expected)
+	}
+
+	if clusters := levenshtein.ClusterByDistance(nil, 1); len(clusters) != 0 {
+		t.Errorf("ClusterByDistance(nil) = %v, expected no clusters", clusters)
+	}
+}
+
+func TestSearchNegativeDistances(t *testing.T) {
+	// With a word boundary bonus, distances can be negative, so that a
+	// negative distance must not be mistaken for the absence of one
+	bonus := levenshtein.WordBoundaryBonus(3)
+	if dist := levenshtein.DistanceToSet("ab cd", []string{"ab cd", "ab cx"}, bonus); dist != -6 {
+		t.Errorf("DistanceToSet with WordBoundaryBonus = %d, expected -6", dist)
+	}
+	if dist := levenshtein.DistanceToSet("ab cd", []string{"ab cx", "ab cd"}, bonus); dist != -6 {
+		t.Errorf("DistanceToSet with WordBoundaryBonus = %d, expected -6", dist)
+	}
+	if index, _ := levenshtein.BestBase("ab cd", []string{"ab cd", "ab cx"}, bonus); index != 0 {
+		t.Errorf("BestBase with WordBoundaryBonus = %d, expected 0", index)
+	}
+	if dist := levenshtein.CircularDistance("ab cd", "ab cd", bonus); dist != -6 {
+		t.Errorf("CircularDistance with WordBoundaryBonus = %d, expected -6", dist)
+	}
+
+	r := levenshtein.NewRollingComparator(2, bonus)
+	r.Add("ab cd")
+	r.Add("ab cx")
+	if dist, closest := r.Add("ab cd"); dist != -6 || closest != "ab cd" {
+		t.Errorf("RollingComparator.Add with WordBoundaryBonus = %d, %q, expected -6, %q", dist, closest, "ab cd")
+	}
+
+	stream := make(chan string, 3)
+	for _, candidate := range []string{"ab cd", "zzzzzzzzzzzz", "qqqqqqqqqqqqqqqq"} {
+		stream <- candidate
+	}
+	close(stream)
+	if matches := levenshtein.StreamTopK("ab cd", stream, 1, bonus); len(matches) != 1 || matches[0].Candidate != "ab cd" || matches[0].Distance != -6 {
+		t.Errorf("StreamTopK with WordBoundaryBonus = %v, expected only %q at distance -6", matches, "ab cd")
+	}
+}
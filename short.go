@@ -0,0 +1,71 @@
+package levenshtein
+
+// shortLimit is the maximum length (in runes) of the strings for which
+// DistanceOnly avoids heap allocation.
+const shortLimit = 64
+
+// DistanceOnly returns the edit distance between the two strings, without
+// building or retaining an edit matrix. It is equivalent to
+// Distance(source, target, options...), but only two rows of the matrix are
+// calculated. When no options are provided and both strings are at most 64
+// runes long (the common case of comparing short words), the calculation uses
+// fixed-size buffers on the stack, so it does not allocate at all.
+func DistanceOnly(source, target string, options ...Option) int {
+	if len(options) == 0 {
+		if dist, ok := shortDistance(source, target); ok {
+			return dist
+		}
+	}
+
+	m := newConfig(options)
+	m.source = m.prepare(source)
+	t := m.prepare(target)
+	dist, _ := m.rowDistance(t, -1, make([]int, 2*(len(t)+1)))
+	return dist
+}
+
+// shortDistance calculates the edit distance between the two strings using
+// the default costs and stack buffers, and returns false if either string is
+// longer than shortLimit runes.
+func shortDistance(source, target string) (int, bool) {
+	var s, t [shortLimit]rune
+	var prevBuf, rowBuf [shortLimit + 1]int
+
+	n := 0
+	for _, r := range source {
+		if n == shortLimit {
+			return 0, false
+		}
+		s[n] = r
+		n++
+	}
+	m := 0
+	for _, r := range target {
+		if m == shortLimit {
+			return 0, false
+		}
+		t[m] = r
+		m++
+	}
+
+	prev, row := prevBuf[:m+1], rowBuf[:m+1]
+	for j := range prev {
+		prev[j] = j * DefaultInsertCost
+	}
+	for i := 1; i <= n; i++ {
+		row[0] = prev[0] + DefaultRemoveCost
+		for j := 1; j <= m; j++ {
+			swap := DefaultSwapCost
+			if s[i-1] == t[j-1] {
+				swap = 0
+			}
+			row[j] = min(
+				row[j-1]+DefaultInsertCost,
+				prev[j]+DefaultRemoveCost,
+				prev[j-1]+swap,
+			)
+		}
+		prev, row = row, prev
+	}
+	return prev[m], true
+}
@@ -0,0 +1,165 @@
+package levenshtein
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Score calculates the edit distance between the two strings, giving up once
+// it is known to exceed max, and reports both whether the distance is within
+// max and the similarity ratio of the two strings. The ratio is 1 minus the
+// distance divided by the length (in runes) of the longer string, so it is 1
+// for identical strings, and is floored at 0 when the distance is greater
+// than the length of the longer string (which is possible with non-default
+// costs), and capped at 1 when the distance is negative (which is possible
+// with WordBoundaryBonus). If the distance exceeds max, the ratio is 0.
+func Score(source, target string, max int, options ...Option) (withinMax bool, ratio float64) {
+	p := Compile(source, options...)
+	t := p.config.prepare(target)
+	dist, ok := p.distance(t, max)
+	if !ok {
+		return false, 0
+	}
+	return true, similarityRatio(dist, len(p.config.source), len(t))
+}
+
+// SimilarityRatio returns the similarity ratio of the two strings (see Score),
+// which is 1 minus their edit distance divided by the length (in runes) of the
+// longer string, clamped to between 0 and 1.
+func SimilarityRatio(a, b string, options ...Option) float64 {
+	_, ratio := Score(a, b, -1, options...)
+	return ratio
+}
+
+// RatioBatch returns the similarity ratio (see SimilarityRatio) of each pair
+// of strings, in the same order as the pairs. The pairs are compared in
+// parallel, using as many goroutines as GOMAXPROCS, each of which reuses a
+// single workspace for all of its pairs, which makes it suitable for bulk
+// scoring pipelines.
+func RatioBatch(pairs [][2]string, options ...Option) []float64 {
+	ratios := make([]float64, len(pairs))
+	config := newConfig(options)
+
+	pairsCh := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var rows []int
+			for i := range pairsCh {
+				m := *config
+				m.source = config.prepare(pairs[i][0])
+				target := config.prepare(pairs[i][1])
+				if size := 2 * (len(target) + 1); cap(rows) < size {
+					rows = make([]int, size)
+				}
+				dist, _ := m.rowDistance(target, -1, rows)
+				ratios[i] = similarityRatio(dist, len(m.source), len(target))
+			}
+		}()
+	}
+	for i := range pairs {
+		pairsCh <- i
+	}
+	close(pairsCh)
+	wg.Wait()
+	return ratios
+}
+
+// similarityRatio converts an edit distance between two strings of the given
+// lengths (in runes) into a similarity ratio between 0 and 1.
+func similarityRatio(dist, sourceLen, targetLen int) float64 {
+	length := sourceLen
+	if targetLen > length {
+		length = targetLen
+	}
+	if length == 0 || dist <= 0 {
+		return 1
+	}
+	if ratio := 1 - float64(dist)/float64(length); ratio > 0 {
+		return ratio
+	}
+	return 0
+}
+
+// SimilarityPercent returns the similarity ratio of the two strings (see
+// Score) as a whole percentage between 0 and 100, for display. The percentage
+// is rounded to the nearest integer, with halves rounded up, so a ratio of
+// 0.875 is 88%. It is calculated using integer arithmetic, so it is not
+// subject to floating point rounding errors. If the target string cannot be
+// reached within the limits on the number of operations (see MaxInserts),
+// the percentage is 0.
+func SimilarityPercent(a, b string, options ...Option) int {
+	m := Build(a, b, options...)
+	length := len(m.source)
+	if len(m.target) > length {
+		length = len(m.target)
+	}
+	if length == 0 {
+		return 100
+	}
+	if m.infeasible() {
+		return 0
+	}
+	same := length - m.Distance()
+	if same <= 0 {
+		return 0
+	}
+	if same > length {
+		return 100 // The distance is negative, with WordBoundaryBonus
+	}
+	// Equivalent to rounding 100*same/length half up
+	return (200*same + length) / (2 * length)
+}
+
+// NGramSimilarity returns the similarity of the two strings according to the
+// Dice coefficient of their character n-grams: twice the number of n-grams
+// the strings have in common, divided by the total number of n-grams in both.
+// N-grams are counted as multisets, so repeated n-grams are only shared as
+// many times as they occur in both strings. The result is between 0 (no
+// n-grams in common) and 1 (identical n-grams). This is much cheaper than the
+// edit distance for long strings, and often a good enough approximation.
+//
+// A string which is shorter than n (but not empty) is treated as a single
+// n-gram, and two empty strings are considered identical. An n less than 1 is
+// treated as 1.
+func NGramSimilarity(a, b string, n int) float64 {
+	if n < 1 {
+		n = 1
+	}
+	gramsA, gramsB := ngrams([]rune(a), n), ngrams([]rune(b), n)
+	total := len(gramsA) + len(gramsB)
+	if total == 0 {
+		return 1
+	}
+
+	counts := make(map[string]int, len(gramsA))
+	for _, gram := range gramsA {
+		counts[gram]++
+	}
+	var shared int
+	for _, gram := range gramsB {
+		if counts[gram] > 0 {
+			counts[gram]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(total)
+}
+
+// ngrams returns the n-grams of the given runes, or the runes themselves as a
+// single n-gram if there are fewer than n of them.
+func ngrams(runes []rune, n int) []string {
+	if len(runes) == 0 {
+		return nil
+	}
+	if len(runes) < n {
+		return []string{string(runes)}
+	}
+	grams := make([]string, 0, len(runes)-n+1)
+	for i := 0; i+n <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+n]))
+	}
+	return grams
+}
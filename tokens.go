@@ -0,0 +1,117 @@
+package levenshtein
+
+// TokenMatrix is an edit matrix for two sequences of arbitrary tokens, rather
+// than the characters of two strings. See BuildTokens.
+type TokenMatrix[T any] struct {
+	m      *Matrix
+	source []T
+	target []T
+}
+
+// TokenOperation represents one of the operations performed on the tokens of
+// a source sequence during the process of converting it into a target
+// sequence. It is the token-level equivalent of an Operation: Token is the
+// token which was inserted, removed or kept, or which was swapped in, and
+// FromToken is the token which was swapped out (for swaps only).
+type TokenOperation[T any] struct {
+	Type           OpType
+	Token          T
+	FromToken      T
+	Index          int
+	CumulativeCost int
+}
+
+// BuildTokens builds an edit matrix for two sequences of tokens of any type,
+// using the given function to determine whether two tokens are equal - e.g.
+// to compare structs while ignoring some of their fields. The function must
+// define an equivalence relation (i.e. it must be reflexive, symmetric and
+// transitive). The options which set costs apply as usual, but those which
+// apply to the characters of strings (e.g. IgnoreCase, WildcardRune or
+// ConfusionPairs) have no effect.
+func BuildTokens[T any](source, target []T, equal func(a, b T) bool, options ...Option) *TokenMatrix[T] {
+	// Assign each distinct token a unique rune, so that the tokens can be
+	// compared using an ordinary edit matrix
+	distinct := []T{}
+	toRunes := func(tokens []T) []rune {
+		runes := make([]rune, len(tokens))
+		for i, token := range tokens {
+			id := len(distinct)
+			for k, d := range distinct {
+				if equal(d, token) {
+					id = k
+					break
+				}
+			}
+			if id == len(distinct) {
+				distinct = append(distinct, token)
+			}
+			runes[i] = rune(id)
+		}
+		return runes
+	}
+
+	m := newConfig(options)
+	m.transforms = nil
+	m.resetRuneOptions()
+	m.source = toRunes(source)
+	m.target = toRunes(target)
+	m.allocate()
+	m.fill()
+
+	return &TokenMatrix[T]{
+		m:      m,
+		source: source,
+		target: target,
+	}
+}
+
+// resetRuneOptions disables the options which apply to the characters of
+// strings, for matrices whose "characters" are the IDs of tokens.
+func (m *Matrix) resetRuneOptions() {
+	m.swapFunc = nil
+	m.costs = nil
+	m.markCostOK = false
+	m.caseCostOK = false
+	m.wildcardOK = false
+	m.confusions = nil
+	m.bonus = 0
+}
+
+// Distance returns the edit distance between the two sequences of tokens.
+func (t *TokenMatrix[T]) Distance() int {
+	return t.m.Distance()
+}
+
+// Operations returns a minimal list of edit operations required to transform
+// the source sequence into the target sequence. The Index of each operation
+// has the same meaning as for Operation, but counts tokens rather than
+// characters.
+func (t *TokenMatrix[T]) Operations() []TokenOperation[T] {
+	ops := []TokenOperation[T]{}
+	i, j := 0, 0 // Positions in the source and target sequences
+	t.m.walk(len(t.m.source), len(t.m.target), func(op Operation) bool {
+		tokenOp := TokenOperation[T]{
+			Type:           op.Type,
+			Index:          op.Index,
+			CumulativeCost: op.CumulativeCost,
+		}
+		switch op.Type {
+		case Insert:
+			tokenOp.Token = t.target[j]
+			j++
+		case Remove:
+			tokenOp.Token = t.source[i]
+			i++
+		case Keep:
+			tokenOp.Token = t.target[j]
+			i, j = i+1, j+1
+		case Swap:
+			tokenOp.Token = t.target[j]
+			tokenOp.FromToken = t.source[i]
+			i, j = i+1, j+1
+		}
+		ops = append(ops, tokenOp)
+		return true
+	})
+	return ops
+}
@@ -0,0 +1,122 @@
+package levenshtein_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nathanjcochran/levenshtein"
+)
+
+type event struct {
+	Name string
+	At   time.Time
+}
+
+func sameEvent(a, b event) bool {
+	return a.Name == b.Name
+}
+
+func TestBuildTokens(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return t0.Add(time.Duration(minutes) * time.Minute)
+	}
+	source := []event{{"login", at(0)}, {"view", at(1)}, {"edit", at(2)}, {"logout", at(3)}}
+	target := []event{{"login", at(10)}, {"edit", at(11)}, {"save", at(12)}, {"logout", at(13)}}
+
+	m := levenshtein.BuildTokens(source, target, sameEvent)
+	if dist := m.Distance(); dist != 2 {
+		t.Errorf("Distance() = %d, expected 2", dist)
+	}
+
+	expected := []levenshtein.TokenOperation[event]{
+		{Type: levenshtein.Keep, Token: target[0], Index: 0, CumulativeCost: 0},
+		{Type: levenshtein.Remove, Token: source[1], Index: 1, CumulativeCost: 1},
+		{Type: levenshtein.Keep, Token: target[1], Index: 1, CumulativeCost: 1},
+		{Type: levenshtein.Insert, Token: target[2], Index: 2, CumulativeCost: 2},
+		{Type: levenshtein.Keep, Token: target[3], Index: 3, CumulativeCost: 2},
+	}
+	if ops := m.Operations(); !reflect.DeepEqual(ops, expected) {
+		t.Errorf("Operations() = %v, expected %v", ops, expected)
+	}
+
+	// Costs apply as usual
+	swaps := levenshtein.BuildTokens(source[1:2], target[2:3], sameEvent, levenshtein.SetSwapCost(5))
+	if dist := swaps.Distance(); dist != 2 {
+		t.Errorf("Distance() with swap cost = %d, expected 2", dist)
+	}
+	swap := levenshtein.BuildTokens(source[1:2], target[2:3], sameEvent).Operations()
+	if len(swap) != 1 || swap[0].Type != levenshtein.Swap || swap[0].FromToken != source[1] || swap[0].Token != target[2] {
+		t.Errorf("Operations() = %v, expected a swap of %v for %v", swap, source[1], target[2])
+	}
+}
+
+func TestWordOperations(t *testing.T) {
+	ops := levenshtein.WordOperations("the quick  brown fox", "the slow brown fox jumps")
+	var got []string
+	for _, op := range ops {
+		got = append(got, fmt.Sprintf("%s %s %d", op.Type, op.Token, op.Index))
+	}
+	expected := []string{"keep the 0", "swap slow 1", "keep brown 2", "keep fox 3", "insert jumps 4"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("WordOperations() = %q, expected %q", got, expected)
+	}
+
+	if ops := levenshtein.WordOperations("The Fox", "the fox", levenshtein.IgnoreCase()); len(ops) != 2 || ops[0].Type != levenshtein.Keep || ops[1].Type != levenshtein.Keep {
+		t.Errorf("WordOperations with IgnoreCase = %v, expected two keeps", ops)
+	}
+	if ops := levenshtein.WordOperations("", "  "); len(ops) != 0 {
+		t.Errorf("WordOperations of empty texts = %v, expected none", ops)
+	}
+}
+
+func TestSetWordFrequencies(t *testing.T) {
+	freqs := levenshtein.SetWordFrequencies(map[string]float64{
+		"the":   1,
+		"a":     0.5,
+		"zebra": 0.01,
+		"never": 0,
+	})
+	cost := func(source, target string, options ...levenshtein.Option) int {
+		ops := levenshtein.WordOperations(source, target, options...)
+		if len(ops) == 0 {
+			return 0
+		}
+		return ops[len(ops)-1].CumulativeCost
+	}
+
+	tests := []struct {
+		source, target string
+		expected       int
+	}{
+		{"saw cat", "saw the cat", 1},     // Common word
+		{"saw cat", "saw a cat", 2},       // Less common word
+		{"saw cat", "saw zebra cat", 100}, // Rare word
+		{"saw zebra cat", "saw cat", 100},
+		{"saw cat", "saw big cat", 1},   // Missing from the map
+		{"saw cat", "saw never cat", 1}, // Not weighted
+		{"saw the cat", "saw a cat", 2}, // Swaps are weighted by the rarer word
+		{"saw the cat", "saw the cat", 0},
+	}
+	for _, test := range tests {
+		if got := cost(test.source, test.target, freqs); got != test.expected {
+			t.Errorf("WordOperations(%q, %q) cost = %d, expected %d", test.source, test.target, got, test.expected)
+		}
+	}
+
+	// Inserting a rare word costs more than inserting a common one, which
+	// isn't the case without the frequencies
+	if rare, common := cost("saw cat", "saw zebra cat", freqs), cost("saw cat", "saw the cat", freqs); rare <= common {
+		t.Errorf("rare word cost %d, expected more than common word cost %d", rare, common)
+	}
+	if rare, common := cost("saw cat", "saw zebra cat"), cost("saw cat", "saw the cat"); rare != common {
+		t.Errorf("unweighted rare word cost %d, expected common word cost %d", rare, common)
+	}
+
+	// Frequencies have no effect on character-level matrices
+	if dist := levenshtein.Distance("the", "zebra", freqs); dist != levenshtein.Distance("the", "zebra") {
+		t.Errorf("Distance with SetWordFrequencies = %d, expected unweighted distance", dist)
+	}
+}
@@ -0,0 +1,84 @@
+package levenshtein
+
+// WithTrace is an option which causes the matrix to record, as it is filled,
+// which operations achieved the minimum cost for each cell. The recorded
+// operations can be retrieved with CellOrigin, which is useful for teaching
+// and debugging, since it explains why a particular path through the matrix
+// was chosen. Tracing requires an extra byte of memory per cell.
+func WithTrace() Option {
+	return func(m *Matrix) {
+		m.trace = true
+	}
+}
+
+// CellOrigin returns the operations which achieved the minimum cost for the
+// cell in row i and column j of the matrix. They are listed in the order in
+// which Operations tries them when tracing back a path: by default, Insert,
+// Remove, then Swap or Keep (depending on whether the characters differ), or
+// with the operation given to PreferOperation first. The order does not affect
+// which operations are recorded. The first cell has no origin, so an empty
+// list is returned for it. CellOrigin returns nil if the matrix was not built
+// with the WithTrace option, and panics if i or j is out of range.
+func (m *Matrix) CellOrigin(i, j int) []OpType {
+	if m.origins == nil {
+		return nil
+	}
+	origin := m.origins[i][j]
+	ops := []OpType{}
+	for _, op := range m.opOrder() {
+		if origin&(1<<op) != 0 {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// recordOrigins records the origin of each cell of a filled matrix.
+func (m *Matrix) recordOrigins() {
+	m.origins = make([][]uint8, len(m.source)+1)
+	for i := range m.origins {
+		m.origins[i] = make([]uint8, len(m.target)+1)
+		for j := range m.origins[i] {
+			m.origins[i][j] = m.origin(i, j)
+		}
+	}
+}
+
+// origin returns the operations which achieved the minimum cost for cell
+// (i, j) of a filled matrix, as a bit set of operation types.
+func (m *Matrix) origin(i, j int) uint8 {
+	val := m.at(i, j)
+	var origin uint8
+	if j > 0 && m.arrivesByInsert(i, j, val) {
+		origin |= 1 << Insert
+	}
+	if i > 0 && m.arrivesByRemove(i, j, val) {
+		origin |= 1 << Remove
+	}
+	if i > 0 && j > 0 && m.at(i-1, j-1)+m.swap(i, j) == val {
+		if m.source[i-1] == m.target[j-1] {
+			origin |= 1 << Keep
+		} else {
+			origin |= 1 << Swap
+		}
+	}
+	return origin
+}
+
+// arrivesByInsert reports whether cell (i, j) can be reached at the given cost
+// by an insertion.
+func (m *Matrix) arrivesByInsert(i, j, cost int) bool {
+	if m.affine {
+		return m.gapInsert[i][j] == cost
+	}
+	return m.at(i, j-1)+m.insert(j) == cost
+}
+
+// arrivesByRemove reports whether cell (i, j) can be reached at the given cost
+// by a removal.
+func (m *Matrix) arrivesByRemove(i, j, cost int) bool {
+	if m.affine {
+		return m.gapRemove[i][j] == cost
+	}
+	return m.at(i-1, j)+m.remove(i) == cost
+}
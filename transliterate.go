@@ -0,0 +1,160 @@
+package levenshtein
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// cyrillicToLatin maps Cyrillic letters to their Latin romanizations, loosely
+// following the BGN/PCGN system without diacritics.
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "w", 'ј': "j", 'љ': "lj",
+	'њ': "nj", 'ћ': "c", 'џ': "dz", 'ђ': "dj", 'ѓ': "gj", 'ќ': "kj", 'ѕ': "dz",
+}
+
+// Transliterate is an option which romanizes both strings before the edit
+// matrix is built, so that strings in different scripts can be compared - e.g.
+// "Москва" becomes "Moskva". Diacritics are removed from Latin characters
+// (e.g. "é" becomes "e"), and Cyrillic letters are converted to their
+// closest Latin equivalents. Transliteration is approximate: there are many
+// competing romanization systems, and characters from other scripts are left
+// unchanged. The list of edit operations reflects the romanized strings.
+func Transliterate() Option {
+	return func(m *Matrix) {
+		m.transforms = append(m.transforms, transliterate)
+	}
+}
+
+// transliterate romanizes a string, as described by the Transliterate option.
+func transliterate(s string) string {
+	t := transform.Chain(
+		romanizer{},
+		norm.NFD,
+		runes.Remove(runes.In(unicode.Mn)),
+		norm.NFC,
+	)
+	result, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+// Loose is an option which both case-folds the strings and removes the
+// diacritics from them before the edit matrix is built, so that e.g. "Café"
+// and "cafe" are considered equal. It is equivalent to IgnoreCase, except that
+// accents are also ignored. Unlike Transliterate, characters from other
+// scripts are not romanized. The list of edit operations reflects the
+// normalized strings.
+func Loose() Option {
+	return func(m *Matrix) {
+		m.transforms = append(m.transforms, loosen)
+	}
+}
+
+// loosen removes diacritics from a string and case-folds it, as described by
+// the Loose option.
+func loosen(s string) string {
+	t := transform.Chain(
+		norm.NFD,
+		runes.Remove(runes.In(unicode.Mn)),
+		cases.Fold(),
+		norm.NFC,
+	)
+	result, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+// SlugNormalize is an option which normalizes both strings as URL slugs before
+// the edit matrix is built, so that e.g. "Hello, World!" and "hello-world"
+// are considered equal. The normalization rules are, in order:
+//
+//  1. Diacritics are removed (e.g. "é" becomes "e"), by decomposing the
+//     string and removing the nonspacing marks.
+//  2. Letters are converted to lower case (using unicode.ToLower, so "ß" is
+//     unchanged, unlike with Loose).
+//  3. Each run of characters which are neither letters nor digits (including
+//     spaces, punctuation, and existing hyphens) is replaced with a single
+//     hyphen.
+//  4. Hyphens at the start and end of the string are removed.
+//
+// Letters and digits from scripts other than Latin are kept. The list of edit
+// operations reflects the normalized strings.
+func SlugNormalize() Option {
+	return func(m *Matrix) {
+		m.transforms = append(m.transforms, slugify)
+	}
+}
+
+// slugify normalizes a string as a URL slug, as described by the
+// SlugNormalize option.
+func slugify(s string) string {
+	t := transform.Chain(
+		norm.NFD,
+		runes.Remove(runes.In(unicode.Mn)),
+		norm.NFC,
+	)
+	if result, _, err := transform.String(t, s); err == nil {
+		s = result
+	}
+
+	slug := make([]rune, 0, len(s))
+	gap := false // Whether a run of separators precedes the next character
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			gap = true
+			continue
+		}
+		if gap && len(slug) > 0 {
+			slug = append(slug, '-')
+		}
+		gap = false
+		slug = append(slug, unicode.ToLower(r))
+	}
+	return string(slug)
+}
+
+// romanizer is a transform.Transformer which converts Cyrillic letters to
+// their Latin romanizations, preserving capitalization.
+type romanizer struct {
+	transform.NopResetter
+}
+
+// Transform implements the transform.Transformer interface.
+func (romanizer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		if !atEOF && !utf8.FullRune(src[nSrc:]) {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		r, size := utf8.DecodeRune(src[nSrc:])
+
+		out := src[nSrc : nSrc+size]
+		if latin, ok := cyrillicToLatin[unicode.ToLower(r)]; ok {
+			if unicode.IsUpper(r) && latin != "" {
+				first, n := utf8.DecodeRuneInString(latin)
+				latin = string(unicode.ToUpper(first)) + latin[n:]
+			}
+			out = []byte(latin)
+		}
+
+		if nDst+len(out) > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += copy(dst[nDst:], out)
+		nSrc += size
+	}
+	return nDst, nSrc, nil
+}
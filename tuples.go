@@ -0,0 +1,72 @@
+package levenshtein
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// OpTuple is a minimal form of an edit operation, holding only its type and
+// the character it affects (the character swapped in, for swaps), e.g. for
+// compact wire transmission as a protobuf message. Unlike an Operation, it
+// has no index, so a list of tuples must cover the whole source string,
+// including its kept characters, to be applied.
+type OpTuple struct {
+	Type OpType
+	Char rune
+}
+
+// Tuples returns the minimal list of edit operations as tuples, including
+// keeps, which can be applied to the source string with ApplyTuples to yield
+// the target string.
+func (m *Matrix) Tuples() []OpTuple {
+	tuples := make([]OpTuple, 0, len(m.source)+len(m.target))
+	m.walk(len(m.source), len(m.target), func(op Operation) bool {
+		tuples = append(tuples, OpTuple{Type: op.Type, Char: op.Char})
+		return true
+	})
+	return tuples
+}
+
+// ApplyTuples performs a list of edit operations, in the form returned by
+// Tuples, on the source string, returning the result. The tuples are applied
+// in order, each one consuming the next character of the source string
+// (except for insertions), so they must cover the whole source string. An
+// error is returned if the character a removal or keep expects does not
+// match the next character of the source string, or if the tuples do not
+// consume exactly the whole source string.
+func ApplyTuples(source string, tuples []OpTuple) (string, error) {
+	var b strings.Builder
+	b.Grow(len(source))
+	pos := 0 // Number of runes of the source consumed so far
+	for n, t := range tuples {
+		if t.Type == Insert {
+			b.WriteRune(t.Char)
+			continue
+		}
+
+		if len(source) == 0 {
+			return "", fmt.Errorf("tuple %d (%s): end of source at index %d", n, t.Type, pos)
+		}
+		r, size := utf8.DecodeRuneInString(source)
+		switch t.Type {
+		case Remove, Keep:
+			if r != t.Char {
+				return "", fmt.Errorf("tuple %d (%s): expected %q at index %d, found %q", n, t.Type, t.Char, pos, r)
+			}
+			if t.Type == Keep {
+				b.WriteRune(r)
+			}
+		case Swap:
+			b.WriteRune(t.Char)
+		default:
+			return "", fmt.Errorf("tuple %d: invalid operation type %d", n, t.Type)
+		}
+		source = source[size:]
+		pos++
+	}
+	if len(source) > 0 {
+		return "", fmt.Errorf("%d characters of source not covered by tuples", utf8.RuneCountInString(source))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,112 @@
+package levenshtein
+
+import (
+	"math"
+	"strings"
+)
+
+// SetWordFrequencies is an option for WordOperations which weights the cost
+// of inserting or removing each word inversely to its document frequency,
+// TF-IDF style, so that edits to common words matter less than edits to rare
+// ones. The frequencies are the proportion of documents in which each word
+// appears, between 0 and 1: the insertion and removal costs of a word with
+// frequency f are divided by f (and rounded), so a word which appears in
+// every document costs the usual amount, and one which appears in 1% of them
+// costs 100 times as much. A swap costs the swap cost weighted by the rarer
+// of the two words. Words which are missing from the map, or which have a
+// frequency of zero or less, are not weighted. The option has no effect on
+// character-level edit matrices.
+func SetWordFrequencies(freqs map[string]float64) Option {
+	return func(m *Matrix) {
+		m.wordFreqs = freqs
+	}
+}
+
+// WordOperations returns a minimal list of word-level edit operations
+// required to transform the source text into the target text, treating each
+// run of non-whitespace characters as a single word which can be inserted,
+// removed, kept, or swapped for another word. The Index of each operation has
+// the same meaning as for Operation, but counts words rather than characters.
+// The options which set costs apply as usual, as do the options which
+// transform the texts (e.g. IgnoreCase), which are applied before the texts
+// are split into words, but the other options which apply to the characters
+// of strings (e.g. WildcardRune or ConfusionPairs) have no effect. See also
+// SetWordFrequencies.
+func WordOperations(source, target string, options ...Option) []TokenOperation[string] {
+	m := newConfig(options)
+	sourceWords := strings.Fields(string(m.prepare(source)))
+	targetWords := strings.Fields(string(m.prepare(target)))
+
+	// Assign each distinct word a unique rune, so that the words can be
+	// compared using an ordinary edit matrix
+	ids := map[string]rune{}
+	words := []string{}
+	toRunes := func(ws []string) []rune {
+		runes := make([]rune, len(ws))
+		for i, word := range ws {
+			id, ok := ids[word]
+			if !ok {
+				id = rune(len(words))
+				ids[word] = id
+				words = append(words, word)
+			}
+			runes[i] = id
+		}
+		return runes
+	}
+
+	m.resetRuneOptions()
+	m.source = toRunes(sourceWords)
+	m.target = toRunes(targetWords)
+	if m.wordFreqs != nil {
+		costs := wordCosts{
+			weights: make([]float64, len(words)),
+			insert:  m.insertCost,
+			remove:  m.removeCost,
+			swap:    m.swapCost,
+		}
+		for id, word := range words {
+			costs.weights[id] = 1
+			if f := m.wordFreqs[word]; f > 0 {
+				costs.weights[id] = 1 / f
+			}
+		}
+		m.costs = costs
+	}
+	m.allocate()
+	m.fill()
+
+	t := TokenMatrix[string]{m: m, source: sourceWords, target: targetWords}
+	return t.Operations()
+}
+
+// wordCosts is the CostModel used by WordOperations to weight the costs of
+// words by their frequencies. The weights are indexed by word ID.
+type wordCosts struct {
+	weights []float64
+	insert  int
+	remove  int
+	swap    int
+}
+
+// Insert implements the CostModel interface.
+func (c wordCosts) Insert(r rune) int {
+	return int(math.Round(float64(c.insert) * c.weights[r]))
+}
+
+// Remove implements the CostModel interface.
+func (c wordCosts) Remove(r rune) int {
+	return int(math.Round(float64(c.remove) * c.weights[r]))
+}
+
+// Swap implements the CostModel interface.
+func (c wordCosts) Swap(from, to rune) int {
+	weight := c.weights[from]
+	if c.weights[to] > weight {
+		weight = c.weights[to]
+	}
+	return int(math.Round(float64(c.swap) * weight))
+}
+
+// Match implements the CostModel interface.
+func (c wordCosts) Match(r rune) int { return 0 }